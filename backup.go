@@ -0,0 +1,569 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/backup"
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+func handlerBackup(s *state, cmd command) error {
+	if len(cmd.args) != 1 && (len(cmd.args) != 3 || cmd.args[1] != "--user") {
+		return fmt.Errorf("usage: %v <file|-> [--user name]", cmd.name)
+	}
+	var userName string
+	if len(cmd.args) == 3 {
+		userName = cmd.args[2]
+	}
+
+	if cmd.args[0] == "-" {
+		return writeBackup(s, os.Stdout, userName)
+	}
+	if err := writeBackupFile(s, cmd.args[0], userName); err != nil {
+		return err
+	}
+	fmt.Printf("backup written to %v\n", cmd.args[0])
+	return nil
+}
+
+// writeBackupFile writes a backup to path, removing the file again if that
+// fails part way.
+func writeBackupFile(s *state, path, userName string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	err = writeBackup(s, f, userName)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// writeBackup writes an archive of every user to w, or only of the named
+// user. A single user's archive has the feeds they added or follow, with
+// feeds added by someone else attributed to them.
+func writeBackup(s *state, w io.Writer, userName string) error {
+	ctx := context.Background()
+	users, err := s.db.GetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve users from db: %w", err)
+	}
+	if userName != "" {
+		users = slices.DeleteFunc(users, func(u database.User) bool { return u.Name != userName })
+		if len(users) == 0 {
+			return fmt.Errorf("no user named %v", userName)
+		}
+	}
+	allFeeds, err := s.db.Feeds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feeds from db: %w", err)
+	}
+
+	exportedUsers := map[uuid.UUID]bool{}
+	followed := map[uuid.UUID]bool{}
+	var follows []database.FeedFollow
+	for _, user := range users {
+		exportedUsers[user.ID] = true
+		userFollows, err := s.db.GetFeedFollowsForUser(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve feed follows from db: %w", err)
+		}
+		for _, follow := range userFollows {
+			followed[follow.FeedID] = true
+		}
+		follows = append(follows, userFollows...)
+	}
+	var feeds []database.Feed
+	for _, feed := range allFeeds {
+		if userName == "" || exportedUsers[feed.UserID] || followed[feed.ID] {
+			feeds = append(feeds, feed)
+		}
+	}
+
+	archive, err := backup.NewWriter(w, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	var records []backup.Record
+	for _, user := range users {
+		records = append(records, backup.Record{User: &backup.User{
+			ID:          user.ID,
+			CreatedAt:   user.CreatedAt,
+			Name:        user.Name,
+			DisplayName: user.DisplayName,
+			Email:       user.Email,
+			Timezone:    user.Timezone,
+			BrowseLimit: user.BrowseLimit,
+		}})
+	}
+	for _, feed := range feeds {
+		owner := feed.UserID
+		if !exportedUsers[owner] {
+			owner = users[0].ID
+		}
+		record := &backup.Feed{
+			ID:               feed.ID,
+			CreatedAt:        feed.CreatedAt,
+			Name:             feed.Name,
+			URL:              feed.Url,
+			UserID:           owner,
+			FetchFullContent: feed.FetchFullContent,
+		}
+		if feed.RetiredAt.Valid {
+			record.RetiredAt = &feed.RetiredAt.Time
+		}
+		if feed.KeepPosts.Valid {
+			record.KeepPosts = &feed.KeepPosts.Int32
+		}
+		if feed.KeepForSeconds.Valid {
+			record.KeepForSeconds = &feed.KeepForSeconds.Int64
+		}
+		records = append(records, backup.Record{Feed: record})
+	}
+	for _, follow := range follows {
+		records = append(records, backup.Record{Follow: &backup.Follow{
+			CreatedAt: follow.CreatedAt,
+			UserID:    follow.UserID,
+			FeedID:    follow.FeedID,
+		}})
+	}
+	for _, record := range records {
+		if err := archive.Write(record); err != nil {
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+
+	// Posts are written a feed at a time to keep memory use down.
+	exportedPosts := map[uuid.UUID]bool{}
+	for _, feed := range feeds {
+		posts, err := s.db.GetPostsByFeedID(ctx, feed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve posts from db: %w", err)
+		}
+		enclosures, err := s.db.GetEnclosuresForFeed(ctx, feed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve enclosures from db: %w", err)
+		}
+		byPost := map[uuid.UUID][]backup.Enclosure{}
+		for _, enclosure := range enclosures {
+			byPost[enclosure.PostID] = append(byPost[enclosure.PostID], backup.Enclosure{
+				URL:      enclosure.Url,
+				Length:   enclosure.Length,
+				MimeType: enclosure.MimeType,
+				Duration: enclosure.Duration,
+				Episode:  nullInt32Ptr(enclosure.Episode),
+				Season:   nullInt32Ptr(enclosure.Season),
+				ImageURL: enclosure.ImageUrl,
+			})
+		}
+		for _, post := range posts {
+			record := &backup.Post{
+				ID:          post.ID,
+				CreatedAt:   post.CreatedAt,
+				FeedID:      post.FeedID,
+				Title:       post.Title,
+				URL:         post.Url,
+				Description: post.Description,
+				PublishedAt: post.PublishedAt,
+				Authors:     post.Authors,
+				Categories:  post.Categories,
+				CommentsURL: post.CommentsUrl,
+				ImageURL:    post.ImageUrl,
+				Enclosures:  byPost[post.ID],
+			}
+			if post.Content.Valid {
+				record.Content = &post.Content.String
+			}
+			if err := archive.Write(backup.Record{Post: record}); err != nil {
+				return fmt.Errorf("failed to write backup: %w", err)
+			}
+			exportedPosts[post.ID] = true
+		}
+	}
+
+	for _, user := range users {
+		states, err := s.db.GetPostStatesForUser(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve post states from db: %w", err)
+		}
+		for _, state := range states {
+			if !exportedPosts[state.PostID] {
+				continue
+			}
+			if err := archive.Write(backup.Record{PostState: &backup.PostState{
+				UserID:  state.UserID,
+				PostID:  state.PostID,
+				Read:    state.Read,
+				Starred: state.Starred,
+			}}); err != nil {
+				return fmt.Errorf("failed to write backup: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func nullInt32Ptr(n sql.NullInt32) *int32 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int32
+}
+
+func ptrNullInt32(n *int32) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *n, Valid: true}
+}
+
+func handlerRestore(s *state, cmd command) error {
+	if len(cmd.args) != 1 {
+		return fmt.Errorf("usage: %v <file|->", cmd.name)
+	}
+	var r io.Reader = os.Stdin
+	if cmd.args[0] != "-" {
+		f, err := os.Open(cmd.args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open backup: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	archive, err := backup.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	var counts restoreCounts
+	err = s.db.InTx(context.Background(), func(q database.Querier) error {
+		var err error
+		counts, err = restoreBackup(q, archive)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored %v users, %v feeds, %v follows, %v posts and %v post states\n",
+		counts.users, counts.feeds, counts.follows, counts.posts, counts.postStates)
+	if counts.existing > 0 {
+		fmt.Printf("%v records were already present\n", counts.existing)
+	}
+	return nil
+}
+
+type restoreCounts struct {
+	users, feeds, follows, posts, postStates int
+	existing                                 int
+}
+
+// restoreBackup adds the records of archive that aren't in the database yet.
+// Users are matched by name, feeds and posts by url, and follows and post
+// states by who and what they are for, so restoring an archive twice
+// changes nothing the second time. Archived ids that are already taken by
+// something else are replaced with new ones.
+func restoreBackup(q database.Querier, archive *backup.Reader) (restoreCounts, error) {
+	ctx := context.Background()
+	var counts restoreCounts
+	users := map[uuid.UUID]uuid.UUID{}
+	feeds := map[uuid.UUID]uuid.UUID{}
+	posts := map[uuid.UUID]uuid.UUID{}
+	// statePosts holds, per user, the posts they already have a state for.
+	statePosts := map[uuid.UUID]map[uuid.UUID]bool{}
+	now := time.Now().UTC()
+
+	// freeID returns id unless lookup finds that it is taken.
+	freeID := func(id uuid.UUID, lookup func(uuid.UUID) error) (uuid.UUID, error) {
+		err := lookup(id)
+		if err == nil {
+			return uuid.New(), nil
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return id, nil
+		}
+		return id, err
+	}
+	mapped := func(ids map[uuid.UUID]uuid.UUID, id uuid.UUID, kind string) (uuid.UUID, error) {
+		if newID, ok := ids[id]; ok {
+			return newID, nil
+		}
+		return id, fmt.Errorf("backup refers to %v %v before it appears", kind, id)
+	}
+
+	for {
+		record, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return counts, nil
+		}
+		if err != nil {
+			return counts, err
+		}
+
+		switch {
+		case record.User != nil:
+			rec := record.User
+			if existing, err := q.GetUser(ctx, rec.Name); err == nil {
+				users[rec.ID] = existing.ID
+				counts.existing++
+				continue
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return counts, fmt.Errorf("failed to retrieve user from db: %w", err)
+			}
+			id, err := freeID(rec.ID, func(id uuid.UUID) error {
+				_, err := q.GetUserByID(ctx, id)
+				return err
+			})
+			if err != nil {
+				return counts, fmt.Errorf("failed to retrieve user from db: %w", err)
+			}
+			if _, err := q.CreateUser(ctx, database.CreateUserParams{
+				ID:        id,
+				CreatedAt: rec.CreatedAt,
+				UpdatedAt: now,
+				Name:      rec.Name,
+			}); err != nil {
+				return counts, fmt.Errorf("failed to create user %v: %w", rec.Name, err)
+			}
+			if rec.DisplayName != "" || rec.Email != "" || rec.Timezone != "" || rec.BrowseLimit != 0 {
+				if _, err := q.UpdateUserProfile(ctx, database.UpdateUserProfileParams{
+					ID:          id,
+					UpdatedAt:   now,
+					DisplayName: rec.DisplayName,
+					Email:       rec.Email,
+					Timezone:    rec.Timezone,
+					BrowseLimit: rec.BrowseLimit,
+				}); err != nil {
+					return counts, fmt.Errorf("failed to restore profile of %v: %w", rec.Name, err)
+				}
+			}
+			users[rec.ID] = id
+			counts.users++
+
+		case record.Feed != nil:
+			rec := record.Feed
+			if existing, err := q.GetFeedByURL(ctx, rec.URL); err == nil {
+				feeds[rec.ID] = existing.ID
+				counts.existing++
+				continue
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return counts, fmt.Errorf("failed to retrieve feed from db: %w", err)
+			}
+			owner, err := mapped(users, rec.UserID, "user")
+			if err != nil {
+				return counts, err
+			}
+			id, err := freeID(rec.ID, func(id uuid.UUID) error {
+				_, err := q.GetFeedByID(ctx, id)
+				return err
+			})
+			if err != nil {
+				return counts, fmt.Errorf("failed to retrieve feed from db: %w", err)
+			}
+			if _, err := q.AddFeed(ctx, database.AddFeedParams{
+				ID:        id,
+				CreatedAt: rec.CreatedAt,
+				UpdatedAt: now,
+				Name:      rec.Name,
+				Url:       rec.URL,
+				UserID:    owner,
+			}); err != nil {
+				return counts, fmt.Errorf("failed to add feed %v: %w", rec.URL, err)
+			}
+			if rec.FetchFullContent {
+				if err := q.SetFeedFetchFullContent(ctx, database.SetFeedFetchFullContentParams{
+					ID:               id,
+					UpdatedAt:        now,
+					FetchFullContent: true,
+				}); err != nil {
+					return counts, fmt.Errorf("failed to update feed: %w", err)
+				}
+			}
+			if rec.RetiredAt != nil {
+				if err := q.RetireFeed(ctx, database.RetireFeedParams{
+					ID:        id,
+					UpdatedAt: now,
+					RetiredAt: sql.NullTime{Time: *rec.RetiredAt, Valid: true},
+				}); err != nil {
+					return counts, fmt.Errorf("failed to update feed: %w", err)
+				}
+			}
+			if rec.KeepPosts != nil || rec.KeepForSeconds != nil {
+				params := database.SetFeedRetentionParams{
+					ID:        id,
+					UpdatedAt: now,
+					KeepPosts: ptrNullInt32(rec.KeepPosts),
+				}
+				if rec.KeepForSeconds != nil {
+					params.KeepForSeconds = sql.NullInt64{Int64: *rec.KeepForSeconds, Valid: true}
+				}
+				if err := q.SetFeedRetention(ctx, params); err != nil {
+					return counts, fmt.Errorf("failed to update feed: %w", err)
+				}
+			}
+			feeds[rec.ID] = id
+			counts.feeds++
+
+		case record.Follow != nil:
+			rec := record.Follow
+			userID, err := mapped(users, rec.UserID, "user")
+			if err != nil {
+				return counts, err
+			}
+			feedID, err := mapped(feeds, rec.FeedID, "feed")
+			if err != nil {
+				return counts, err
+			}
+			follows, err := q.GetFeedFollowsForUser(ctx, userID)
+			if err != nil {
+				return counts, fmt.Errorf("failed to retrieve feed follows from db: %w", err)
+			}
+			if slices.ContainsFunc(follows, func(f database.FeedFollow) bool { return f.FeedID == feedID }) {
+				counts.existing++
+				continue
+			}
+			if _, err := q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+				ID:        uuid.New(),
+				CreatedAt: rec.CreatedAt,
+				UpdatedAt: now,
+				UserID:    userID,
+				FeedID:    feedID,
+			}); err != nil {
+				return counts, fmt.Errorf("failed to create feed follow: %w", err)
+			}
+			counts.follows++
+
+		case record.Post != nil:
+			rec := record.Post
+			if existing, err := q.GetPostByURL(ctx, rec.URL); err == nil {
+				posts[rec.ID] = existing.ID
+				counts.existing++
+				continue
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return counts, fmt.Errorf("failed to retrieve post from db: %w", err)
+			}
+			feedID, err := mapped(feeds, rec.FeedID, "feed")
+			if err != nil {
+				return counts, err
+			}
+			id, err := freeID(rec.ID, func(id uuid.UUID) error {
+				_, err := q.GetPostByID(ctx, id)
+				return err
+			})
+			if err != nil {
+				return counts, fmt.Errorf("failed to retrieve post from db: %w", err)
+			}
+			var content sql.NullString
+			if rec.Content != nil {
+				content = sql.NullString{String: *rec.Content, Valid: true}
+			}
+			if _, err := q.CreatePost(ctx, database.CreatePostParams{
+				ID:          id,
+				CreatedAt:   rec.CreatedAt,
+				UpdatedAt:   now,
+				Title:       rec.Title,
+				Url:         rec.URL,
+				Description: rec.Description,
+				PublishedAt: rec.PublishedAt,
+				FeedID:      feedID,
+				Content:     content,
+				Authors:     nonNil(rec.Authors),
+				Categories:  nonNil(rec.Categories),
+				CommentsUrl: rec.CommentsURL,
+				ImageUrl:    rec.ImageURL,
+			}); err != nil {
+				return counts, fmt.Errorf("failed to create post %v: %w", rec.URL, err)
+			}
+			for _, enclosure := range rec.Enclosures {
+				if err := q.CreateEnclosure(ctx, database.CreateEnclosureParams{
+					ID:        uuid.New(),
+					CreatedAt: rec.CreatedAt,
+					UpdatedAt: now,
+					PostID:    id,
+					Url:       enclosure.URL,
+					Length:    enclosure.Length,
+					MimeType:  enclosure.MimeType,
+					Duration:  enclosure.Duration,
+					Episode:   ptrNullInt32(enclosure.Episode),
+					Season:    ptrNullInt32(enclosure.Season),
+					ImageUrl:  enclosure.ImageURL,
+				}); err != nil {
+					return counts, fmt.Errorf("failed to create enclosure: %w", err)
+				}
+			}
+			posts[rec.ID] = id
+			counts.posts++
+
+		case record.PostState != nil:
+			rec := record.PostState
+			userID, err := mapped(users, rec.UserID, "user")
+			if err != nil {
+				return counts, err
+			}
+			postID, err := mapped(posts, rec.PostID, "post")
+			if err != nil {
+				return counts, err
+			}
+			// Keep states that already exist, which may have changed since
+			// the backup was taken.
+			if statePosts[userID] == nil {
+				states, err := q.GetPostStatesForUser(ctx, userID)
+				if err != nil {
+					return counts, fmt.Errorf("failed to retrieve post states from db: %w", err)
+				}
+				statePosts[userID] = map[uuid.UUID]bool{}
+				for _, state := range states {
+					statePosts[userID][state.PostID] = true
+				}
+			}
+			if statePosts[userID][postID] {
+				counts.existing++
+				continue
+			}
+			statePosts[userID][postID] = true
+			// Both are upserts on the user and post, so the ids only matter
+			// for the first.
+			if err := q.SetPostRead(ctx, database.SetPostReadParams{
+				ID:        uuid.New(),
+				CreatedAt: now,
+				UpdatedAt: now,
+				UserID:    userID,
+				PostID:    postID,
+				Read:      rec.Read,
+			}); err != nil {
+				return counts, fmt.Errorf("failed to restore post state: %w", err)
+			}
+			if err := q.SetPostStarred(ctx, database.SetPostStarredParams{
+				ID:        uuid.New(),
+				CreatedAt: now,
+				UpdatedAt: now,
+				UserID:    userID,
+				PostID:    postID,
+				Starred:   rec.Starred,
+			}); err != nil {
+				return counts, fmt.Errorf("failed to restore post state: %w", err)
+			}
+			counts.postStates++
+		}
+	}
+}
+
+// nonNil keeps empty lists from being stored as NULL.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -3,14 +3,35 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/brendenwelch/gator/internal/config"
 	"github.com/brendenwelch/gator/internal/database"
+	"github.com/brendenwelch/gator/internal/download"
+	"github.com/brendenwelch/gator/internal/extract"
+	"github.com/brendenwelch/gator/internal/render"
 	"github.com/brendenwelch/gator/internal/rss"
+	"github.com/brendenwelch/gator/internal/secret"
+	"github.com/brendenwelch/gator/internal/tui"
 	"github.com/google/uuid"
+	"golang.org/x/term"
+)
+
+const (
+	defaultAggRetries    = 10
+	defaultAggMaxBackoff = time.Minute
 )
 
 type command struct {
@@ -23,7 +44,11 @@ type commands struct {
 }
 
 func (c *commands) run(s *state, cmd command) error {
-	return c.callbacks[cmd.name](s, cmd)
+	f, ok := c.callbacks[cmd.name]
+	if !ok {
+		return fmt.Errorf("unknown command %v", cmd.name)
+	}
+	return f(s, cmd)
 }
 
 func (c *commands) register(name string, f func(*state, command) error) {
@@ -33,11 +58,11 @@ func (c *commands) register(name string, f func(*state, command) error) {
 
 func handlerLogin(s *state, cmd command) error {
 	if len(cmd.args) == 0 {
-		log.Fatalf("missing username for command %v\n", cmd.name)
+		return fmt.Errorf("missing username for command %v", cmd.name)
 	}
 	_, err := s.db.GetUser(context.Background(), cmd.args[0])
 	if err != nil {
-		log.Fatalf("failed to get user %v from db: %v\n", cmd.args[0], err)
+		return fmt.Errorf("failed to get user %v from db: %w", cmd.args[0], err)
 	}
 	if err := s.cfg.SetUser(cmd.args[0]); err != nil {
 		return err
@@ -48,35 +73,27 @@ func handlerLogin(s *state, cmd command) error {
 
 func handlerRegister(s *state, cmd command) error {
 	if len(cmd.args) == 0 {
-		log.Fatalf("missing username for command %v\n", cmd.name)
+		return fmt.Errorf("missing username for command %v", cmd.name)
 	}
 
 	_, err := s.db.CreateUser(context.Background(), database.CreateUserParams{
 		ID:        uuid.New(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
 		Name:      cmd.args[0],
 	})
 	if err != nil {
-		log.Fatalf("failed to create user %v: %v\n", cmd.args[0], err)
+		return fmt.Errorf("failed to create user %v: %w", cmd.args[0], err)
 	}
 	fmt.Printf("%v has been registered\n", cmd.args[0])
-
+	// Only switch the config once the user is sure to exist.
 	if err := s.cfg.SetUser(cmd.args[0]); err != nil {
-		return err
+		return fmt.Errorf("failed to log in as %v: %w", cmd.args[0], err)
 	}
 	fmt.Printf("%v now logged in\n", cmd.args[0])
 	return nil
 }
 
-func handlerReset(s *state, cmd command) error {
-	if err := s.db.Reset(context.Background()); err != nil {
-		log.Fatalf("failed to reset database: %v\n", err)
-	}
-	fmt.Println("database reset")
-	return nil
-}
-
 func handlerUsers(s *state, cmd command) error {
 	users, err := s.db.GetUsers(context.Background())
 	if err != nil {
@@ -86,10 +103,14 @@ func handlerUsers(s *state, cmd command) error {
 		fmt.Println("registered users:")
 	}
 	for i := range users {
+		name := users[i].Name
+		if users[i].DisplayName != "" {
+			name += " (" + users[i].DisplayName + ")"
+		}
 		if users[i].Name == s.cfg.Current_user_name {
-			fmt.Printf("* %v (current)\n", users[i].Name)
+			fmt.Printf("* %v (current)\n", name)
 		} else {
-			fmt.Printf("* %v\n", users[i].Name)
+			fmt.Printf("* %v\n", name)
 		}
 	}
 	return nil
@@ -97,120 +118,783 @@ func handlerUsers(s *state, cmd command) error {
 
 func handlerAgg(s *state, cmd command) error {
 	if len(cmd.args) < 1 {
-		log.Fatalf("missing time between requests for command %v\n", cmd.name)
+		return fmt.Errorf("missing time between requests for command %v", cmd.name)
 	}
 
 	timeBetweenRequests, err := time.ParseDuration(cmd.args[0])
 	if err != nil {
-		log.Fatalf("failed to parse duration from %v argument %v: %v\n", cmd.name, cmd.args[0], err)
+		return fmt.Errorf("failed to parse duration from %v argument %v: %w", cmd.name, cmd.args[0], err)
+	}
+
+	retries := s.cfg.Agg.Retries
+	if retries == 0 {
+		retries = defaultAggRetries
+	}
+	maxBackoff := defaultAggMaxBackoff
+	if s.cfg.Agg.Max_backoff != "" {
+		if maxBackoff, err = time.ParseDuration(s.cfg.Agg.Max_backoff); err != nil {
+			return fmt.Errorf("failed to parse agg max backoff: %w", err)
+		}
 	}
 
 	ticker := time.NewTicker(timeBetweenRequests)
 	for ; ; <-ticker.C {
-		scrapeFeeds(s)
+		if err := scrapeFeedsWithRetry(s, retries, maxBackoff); err != nil {
+			return err
+		}
+		if s.cfg.Retention.Prune_after_agg {
+			pruned, err := prunePosts(s, false)
+			if err != nil {
+				log.Printf("failed to prune posts: %v", err)
+			}
+			for _, feed := range pruned {
+				log.Printf("pruned %v posts from %v", len(feed.posts), feed.feed.Name)
+			}
+		}
 	}
 }
 
-func scrapeFeeds(s *state) {
-	feed, err := s.db.GetNextFeedToFetch(context.Background())
+// scrapeFeedsWithRetry runs scrapeFeeds, trying again after transient
+// database errors so agg rides out things like a database restart.
+func scrapeFeedsWithRetry(s *state, retries int, maxBackoff time.Duration) error {
+	backoff := min(time.Second, maxBackoff)
+	for attempt := 0; ; attempt++ {
+		err := scrapeFeeds(s)
+		if err == nil || attempt >= retries || !allTransient(err) {
+			return err
+		}
+		log.Printf("database unavailable, retrying in %v: %v", backoff, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// allTransient reports whether err, and every error joined into it, is a
+// transient database error worth retrying.
+func allTransient(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			if !allTransient(err) {
+				return false
+			}
+		}
+		return true
+	}
+	return database.IsTransient(err)
+}
+
+// scrapeFeeds fetches the feeds that have waited longest, as many at once as
+// the agg concurrency allows. Requests to a single host are further limited
+// by the fetcher. A feed that can't be fetched or parsed is logged and
+// skipped, so only database errors are returned.
+func scrapeFeeds(s *state) error {
+	feeds, err := s.db.GetNextFeedsToFetch(context.Background(), database.GetNextFeedsToFetchParams{
+		Now:      time.Now().UTC(),
+		MaxFeeds: int32(max(s.cfg.Agg.Concurrency, 1)),
+	})
 	if err != nil {
-		log.Fatalf("failed to retrieve next feed to fetch from db: %v\n", err)
+		return fmt.Errorf("failed to retrieve next feeds to fetch from db: %w", err)
+	}
+
+	errs := make([]error, len(feeds))
+	var wg sync.WaitGroup
+	for i, feed := range feeds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = scrapeFeed(s, feed)
+		}()
 	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
 
+func scrapeFeed(s *state, feed database.Feed) error {
 	if err := s.db.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
 		ID:        feed.ID,
-		UpdatedAt: time.Now(),
+		UpdatedAt: time.Now().UTC(),
 		LastFetchedAt: sql.NullTime{
-			Time:  time.Now(),
+			Time:  time.Now().UTC(),
 			Valid: true,
 		},
 	}); err != nil {
-		log.Fatalf("failed to mark feed fetched: %v\n", err)
+		return fmt.Errorf("failed to mark feed fetched: %w", err)
 	}
 
-	fetchedfeed, err := rss.FetchFeed(context.Background(), feed.Url)
+	auth, err := feedAuth(s.cfg, feed)
+	if err != nil {
+		log.Printf("skipped %v: %v", feed.Url, err)
+		return nil
+	}
+	fetchedfeed, err := s.fetcher.FetchFeed(context.Background(), feed.Url, auth)
+	var limitErr *rss.RateLimitError
+	if !errors.As(err, &limitErr) {
+		recordFetch(s, feed, fetchedfeed.Stats, err)
+	}
+	if until, ok := rateLimited(err); ok {
+		// Leave the feed alone until the host is willing to talk to us again.
+		if err := s.db.SetFeedNextFetch(context.Background(), database.SetFeedNextFetchParams{
+			ID:        feed.ID,
+			UpdatedAt: time.Now().UTC(),
+			NextFetchAt: sql.NullTime{
+				Time:  until.UTC(),
+				Valid: true,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to postpone feed: %w", err)
+		}
+		log.Printf("%v is rate limited, next fetch after %v", feed.Url, until.Format(time.RFC1123))
+		return nil
+	}
+	var statusErr *rss.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusGone {
+		if err := s.db.RetireFeed(context.Background(), database.RetireFeedParams{
+			ID:        feed.ID,
+			UpdatedAt: time.Now().UTC(),
+			RetiredAt: sql.NullTime{
+				Time:  time.Now().UTC(),
+				Valid: true,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to retire feed: %w", err)
+		}
+		log.Printf("%v returned 410 Gone, retired %v", feed.Url, feed.Name)
+		return nil
+	}
 	if err != nil {
-		log.Fatalf("failed to fetch feed from url: %v\n", err)
+		log.Printf("failed to fetch %v: %v", feed.Url, err)
+		return nil
+	}
+	if fetchedfeed.PermanentURL != "" && fetchedfeed.PermanentURL != feed.Url {
+		feed, err = moveFeed(s, feed, fetchedfeed.PermanentURL)
+		if err != nil {
+			return err
+		}
+	}
+	warnings := fetchedfeed.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	for _, warning := range warnings {
+		log.Printf("%v: %v", feed.Url, render.Clean(warning))
 	}
 
+	type newPost struct {
+		post       database.CreatePostParams
+		enclosures []database.CreateEnclosureParams
+	}
+	var posts []newPost
+	policy := sanitizePolicy(s.cfg)
 	for _, item := range fetchedfeed.Channel.Item {
-		pubDate, err := time.Parse("Mon, 02 Jan 2006 03:04:05 -07:00", item.PubDate)
+		// The link is opened in a browser later, so only keep web pages.
+		link := policy.CleanURL(item.Link, "")
+		if !isWebURL(link) {
+			log.Printf("%v: skipped %q, its link is not a web page", feed.Url, item.Title)
+			continue
+		}
+		pubDate, err := rss.ParseDate(item.PubDate)
 		if err != nil {
-			pubDate = time.Now()
+			pubDate = time.Now().UTC()
 		}
-		if err = s.db.CreatePost(context.Background(), database.CreatePostParams{
+		var content sql.NullString
+		if item.Content != "" {
+			content = sql.NullString{
+				String: policy.Sanitize(item.Content, link),
+				Valid:  true,
+			}
+		}
+		image := item.Thumbnail()
+		if feed.FetchFullContent {
+			// Skip posts we already have rather than download them again.
+			if _, err := s.db.GetPostByURL(context.Background(), link); err == nil {
+				continue
+			}
+			page, err := extract.Article(context.Background(), s.fetcher.Client, link)
+			if err != nil {
+				log.Printf("failed to extract full content of %v: %v", link, err)
+			} else {
+				content = sql.NullString{
+					String: policy.Sanitize(page.Content, link),
+					Valid:  true,
+				}
+			}
+			if image == "" {
+				image = page.Image
+			}
+		}
+		post := newPost{post: database.CreatePostParams{
 			ID:          uuid.New(),
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			CreatedAt:   time.Now().UTC(),
+			UpdatedAt:   time.Now().UTC(),
 			Title:       item.Title,
-			Url:         item.Link,
-			Description: item.Description,
+			Url:         link,
+			Description: policy.Sanitize(item.Description, link),
 			PublishedAt: pubDate,
 			FeedID:      feed.ID,
+			Content:     content,
+			Authors:     item.Authors(),
+			Categories:  item.Categories(),
+			CommentsUrl: item.Comments,
+			ImageUrl:    policy.CleanURL(image, link),
+		}}
+		for _, enclosure := range item.Enclosure {
+			if enclosure.URL == "" {
+				continue
+			}
+			length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+			post.enclosures = append(post.enclosures, database.CreateEnclosureParams{
+				ID:        uuid.New(),
+				CreatedAt: time.Now().UTC(),
+				UpdatedAt: time.Now().UTC(),
+				PostID:    post.post.ID,
+				Url:       enclosure.URL,
+				Length:    length,
+				MimeType:  enclosure.Type,
+				Duration:  item.Duration,
+				Episode:   nullInt32(item.Episode),
+				Season:    nullInt32(item.Season),
+				ImageUrl:  policy.CleanURL(item.Image.Href, link),
+			})
+		}
+		posts = append(posts, post)
+	}
+
+	// Store everything from this fetch in one go, so a failure doesn't leave
+	// posts without their enclosures or the feed half updated.
+	return s.db.InTx(context.Background(), func(q database.Querier) error {
+		if err := q.SetFeedParseWarnings(context.Background(), database.SetFeedParseWarningsParams{
+			ID:            feed.ID,
+			UpdatedAt:     time.Now().UTC(),
+			ParseWarnings: warnings,
 		}); err != nil {
-			log.Printf("failed to create post in db: %v", err)
+			return fmt.Errorf("failed to record feed warnings: %w", err)
+		}
+		for _, post := range posts {
+			created, err := q.CreatePost(context.Background(), post.post)
+			if err != nil {
+				return fmt.Errorf("failed to create post in db: %w", err)
+			}
+			if created == 0 {
+				// We already have a post with this url.
+				continue
+			}
+			for _, enclosure := range post.enclosures {
+				if err := q.CreateEnclosure(context.Background(), enclosure); err != nil {
+					return fmt.Errorf("failed to create enclosure in db: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// recordFetch logs the cost of a feed request for `stats fetch`. Failing to
+// record it doesn't stop the scrape.
+func recordFetch(s *state, feed database.Feed, stats rss.FetchStats, fetchErr error) {
+	var errText string
+	if fetchErr != nil {
+		errText = fetchErr.Error()
+	}
+	if err := s.db.CreateFetch(context.Background(), database.CreateFetchParams{
+		ID:               uuid.New(),
+		CreatedAt:        time.Now().UTC(),
+		FeedID:           feed.ID,
+		StatusCode:       int32(stats.StatusCode),
+		BytesTransferred: stats.BytesTransferred,
+		BytesDecoded:     stats.BytesDecoded,
+		DurationMs:       stats.Duration.Milliseconds(),
+		Error:            errText,
+	}); err != nil {
+		log.Printf("failed to record fetch of %v: %v", feed.Url, err)
+	}
+}
+
+// rateLimited reports whether err means the feed's host asked us to back off,
+// and until when.
+func rateLimited(err error) (time.Time, bool) {
+	var limitErr *rss.RateLimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Until, true
+	}
+	var statusErr *rss.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+		return time.Now().UTC().Add(statusErr.RetryAfter), true
+	}
+	return time.Time{}, false
+}
+
+// moveFeed points feed at newURL after a permanent redirect. If another feed
+// already has that url, follows and posts are merged into it and it is
+// returned instead. A feed with credentials keeps its url if the redirect
+// leads to another host, since they would be sent there on every fetch.
+func moveFeed(s *state, feed database.Feed, newURL string) (database.Feed, error) {
+	if feed.Credentials != nil && !sameHost(feed.Url, newURL) {
+		log.Printf("%v permanently moved to %v, kept the old url so its credentials aren't sent to another host", feed.Url, newURL)
+		return feed, nil
+	}
+	existing, err := s.db.GetFeedByURL(context.Background(), newURL)
+	if err == nil {
+		err := s.db.InTx(context.Background(), func(q database.Querier) error {
+			if err := q.MergeFeedFollows(context.Background(), database.MergeFeedFollowsParams{
+				UpdatedAt:  time.Now().UTC(),
+				IntoFeedID: existing.ID,
+				FromFeedID: feed.ID,
+			}); err != nil {
+				return fmt.Errorf("failed to merge feed follows: %w", err)
+			}
+			if err := q.MergeFeedPosts(context.Background(), database.MergeFeedPostsParams{
+				IntoFeedID: existing.ID,
+				FromFeedID: feed.ID,
+			}); err != nil {
+				return fmt.Errorf("failed to merge feed posts: %w", err)
+			}
+			if err := q.DeleteFeed(context.Background(), feed.ID); err != nil {
+				return fmt.Errorf("failed to delete merged feed: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return feed, err
+		}
+		log.Printf("%v permanently moved to %v, merged into %v", feed.Url, newURL, existing.Name)
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return feed, fmt.Errorf("failed to retrieve feed from db: %w", err)
+	}
+
+	if err := s.db.UpdateFeedURL(context.Background(), database.UpdateFeedURLParams{
+		ID:        feed.ID,
+		UpdatedAt: time.Now().UTC(),
+		Url:       newURL,
+	}); err != nil {
+		return feed, fmt.Errorf("failed to update feed url: %w", err)
+	}
+	log.Printf("%v permanently moved to %v", feed.Url, newURL)
+	feed.Url = newURL
+	return feed, nil
+}
+
+// sameHost reports whether a and b are urls on the same host and port.
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host)
+}
+
+// isWebURL reports whether raw is an absolute http or https URL.
+func isWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+func nullInt32(s string) sql.NullInt32 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+	if err != nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(n), Valid: true}
+}
+
+func sanitizePolicy(cfg *config.Config) rss.Policy {
+	policy := rss.DefaultPolicy()
+	for element, attrs := range cfg.Sanitizer.Allow_elements {
+		policy.Allow(element, attrs...)
+	}
+	if len(cfg.Sanitizer.Url_schemes) > 0 {
+		policy.URLSchemes = cfg.Sanitizer.Url_schemes
+	}
+	policy.StripTrackingImages = !cfg.Sanitizer.Keep_tracking_images
+	return policy
+}
+
+func newFetcher(cfg *config.Config) (*rss.Fetcher, error) {
+	opts := rss.FetcherOptions{
+		MaxBodySize:     cfg.Fetch.Max_body_size,
+		UserAgent:       cfg.Fetch.User_agent,
+		ContactURL:      cfg.Fetch.Contact_url,
+		ProxyURL:        cfg.Fetch.Proxy_url,
+		CABundle:        cfg.Fetch.Ca_bundle,
+		MaxRedirects:    cfg.Fetch.Max_redirects,
+		HostRate:        cfg.Fetch.Host_rate,
+		HostBurst:       cfg.Fetch.Host_burst,
+		HostConcurrency: cfg.Fetch.Host_concurrency,
+	}
+	var err error
+	if cfg.Fetch.Timeout != "" {
+		if opts.Timeout, err = time.ParseDuration(cfg.Fetch.Timeout); err != nil {
+			return nil, fmt.Errorf("failed to parse fetch timeout: %w", err)
+		}
+	}
+	if cfg.Fetch.Connect_timeout != "" {
+		if opts.ConnectTimeout, err = time.ParseDuration(cfg.Fetch.Connect_timeout); err != nil {
+			return nil, fmt.Errorf("failed to parse fetch connect timeout: %w", err)
 		}
 	}
+	return rss.NewFetcher(opts)
 }
 
 func handlerBrowse(s *state, cmd command, user database.User) error {
-	var limit int32 = 2
+	var limit int32 = defaultBrowseLimit
+	if user.BrowseLimit > 0 {
+		limit = user.BrowseLimit
+	}
 	if len(cmd.args) > 0 {
-		arg, err := strconv.Atoi(cmd.args[0])
-		if err == nil {
-			limit = int32(arg)
+		arg, err := strconv.ParseInt(cmd.args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("failed to parse browse limit from %v: %w", cmd.args[0], err)
 		}
+		if arg < 1 {
+			return fmt.Errorf("browse limit must be at least 1, got %v", arg)
+		}
+		limit = int32(arg)
 	}
 
+	loc, err := userLocation(s, user)
+	if err != nil {
+		return err
+	}
 	posts, err := s.db.GetPostsByUser(context.Background(), database.GetPostsByUserParams{
 		Name:  user.Name,
 		Limit: limit,
 	})
 	if err != nil {
-		log.Fatalf("failed to retrieve posts from db: %v", err)
+		return fmt.Errorf("failed to retrieve posts from db: %w", err)
 	}
 	fmt.Printf("%v most recent posts followed by %v:\n", limit, user.Name)
 	for _, post := range posts {
-		fmt.Printf("- %v\n", post.Title)
+		fmt.Printf("- %v\n  %v\n", render.Clean(post.Title), render.Clean(post.Url))
+		fmt.Printf("  published %v\n", post.PublishedAt.In(loc).Format(time.RFC1123))
+		if len(post.Authors) > 0 {
+			fmt.Printf("  by %v\n", render.Clean(strings.Join(post.Authors, ", ")))
+		}
+		if len(post.Categories) > 0 {
+			fmt.Printf("  categories: %v\n", render.Clean(strings.Join(post.Categories, ", ")))
+		}
+		if post.CommentsUrl != "" {
+			fmt.Printf("  comments: %v\n", render.Clean(post.CommentsUrl))
+		}
+		if post.ImageUrl != "" {
+			fmt.Printf("  image: %v\n", render.Clean(post.ImageUrl))
+		}
+		enclosures, err := s.db.GetEnclosuresForPost(context.Background(), post.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve enclosures from db: %w", err)
+		}
+		for _, enclosure := range enclosures {
+			fmt.Printf("  enclosure: %v%v\n", render.Clean(enclosure.Url), render.Clean(enclosureDetails(enclosure)))
+		}
 	}
 
 	return nil
 }
 
-func handlerAddFeed(s *state, cmd command, user database.User) error {
-	if len(cmd.args) < 2 {
-		log.Fatalf("missing name, url for command %v\n", cmd.name)
+func handlerRead(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("missing post id or url for command %v", cmd.name)
 	}
 
-	feed, err := s.db.AddFeed(context.Background(), database.AddFeedParams{
-		ID:        uuid.New(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Name:      cmd.args[0],
-		Url:       cmd.args[1],
-		UserID:    user.ID,
-	})
+	var post database.Post
+	var err error
+	if id, parseErr := uuid.Parse(cmd.args[0]); parseErr == nil {
+		post, err = s.db.GetPostByID(context.Background(), id)
+	} else {
+		post, err = s.db.GetPostByURL(context.Background(), cmd.args[0])
+	}
 	if err != nil {
-		log.Fatalf("failed to add feed to db: %v\n", err)
+		return fmt.Errorf("failed to retrieve post from db: %w", err)
 	}
-	feedfollow, err := s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+
+	loc, err := userLocation(s, user)
+	if err != nil {
+		return err
+	}
+	opts := render.Options{Width: 80, BaseURL: post.Url}
+	if fd := int(os.Stdout.Fd()); term.IsTerminal(fd) {
+		if width, _, err := term.GetSize(fd); err == nil {
+			opts.Width = min(width, 100)
+		}
+		opts.Color = os.Getenv("NO_COLOR") == ""
+	}
+	fmt.Println(render.Clean(post.Title))
+	fmt.Println(post.PublishedAt.In(loc).Format(time.RFC1123))
+	if len(post.Authors) > 0 {
+		fmt.Printf("by %v\n", render.Clean(strings.Join(post.Authors, ", ")))
+	}
+	fmt.Println(render.Clean(post.Url))
+	if post.ImageUrl != "" {
+		fmt.Printf("image: %v\n", render.Clean(post.ImageUrl))
+	}
+	fmt.Println()
+	body := post.Description
+	if post.Content.Valid {
+		body = post.Content.String
+	}
+	fmt.Println(render.Render(body, opts))
+
+	if err := s.db.SetPostRead(context.Background(), database.SetPostReadParams{
 		ID:        uuid.New(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
 		UserID:    user.ID,
-		FeedID:    feed.ID,
+		PostID:    post.ID,
+		Read:      true,
+	}); err != nil {
+		return fmt.Errorf("failed to mark post read: %w", err)
+	}
+	return nil
+}
+
+func handlerTUI(s *state, cmd command, user database.User) error {
+	loc, err := userLocation(s, user)
+	if err != nil {
+		return err
+	}
+	return tui.Run(context.Background(), s.db, user, loc, s.stdin)
+}
+
+func enclosureDetails(enclosure database.Enclosure) string {
+	var details []string
+	if enclosure.MimeType != "" {
+		details = append(details, enclosure.MimeType)
+	}
+	if enclosure.Length > 0 {
+		details = append(details, fmt.Sprintf("%.1f MB", float64(enclosure.Length)/(1<<20)))
+	}
+	if enclosure.Duration != "" {
+		details = append(details, enclosure.Duration)
+	}
+	if enclosure.Season.Valid {
+		details = append(details, fmt.Sprintf("season %v", enclosure.Season.Int32))
+	}
+	if enclosure.Episode.Valid {
+		details = append(details, fmt.Sprintf("episode %v", enclosure.Episode.Int32))
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+func handlerDownload(s *state, cmd command) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("missing url for command %v", cmd.name)
+	}
+	dir := s.cfg.Download_dir
+	if len(cmd.args) > 1 {
+		dir = cmd.args[1]
+	}
+	if dir == "" {
+		dir = "."
+	}
+	keep := s.cfg.Download_keep
+	if len(cmd.args) > 2 {
+		n, err := strconv.Atoi(cmd.args[2])
+		if err != nil {
+			return fmt.Errorf("failed to parse number of episodes to keep from %v: %w", cmd.args[2], err)
+		}
+		keep = n
+	}
+
+	feed, err := s.db.GetFeedByURL(context.Background(), cmd.args[0])
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feed from db: %w", err)
+	}
+	enclosures, err := s.db.GetEnclosuresForFeed(context.Background(), feed.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve enclosures from db: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	// Use the feed fetcher's proxy, CA bundle, User-Agent and rate limits, but
+	// not its overall timeout, which is sized for feeds rather than episodes.
+	// download.File gives up on stalled transfers itself.
+	client := *s.fetcher.Client
+	client.Timeout = 0
+	for i, enclosure := range enclosures {
+		path := filepath.Join(dir, enclosureFilename(enclosure))
+		if keep > 0 && i >= keep {
+			// Older than the newest keep episodes.
+			if err := os.Remove(path); err == nil {
+				fmt.Printf("removed %v\n", path)
+			}
+			os.Remove(path + ".part")
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		fmt.Printf("downloading %v\n", render.Clean(enclosure.PostTitle))
+		if err := download.File(context.Background(), &client, enclosure.Url, path); err != nil {
+			return err
+		}
+		fmt.Printf("saved %v\n", path)
+	}
+	return nil
+}
+
+// enclosureFilename prefixes the name from the enclosure url with the
+// publication date, so files sort by date, and the start of the enclosure's
+// id, so episodes that share a name and a day don't collide.
+func enclosureFilename(enclosure database.GetEnclosuresForFeedRow) string {
+	name := "enclosure"
+	if u, err := url.Parse(enclosure.Url); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+		name = path.Base(u.Path)
+	}
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	return enclosure.PublishedAt.Format("2006-01-02") + "-" + enclosure.ID.String()[:8] + "-" + name
+}
+
+func handlerAddFeed(s *state, cmd command, user database.User) error {
+	if len(cmd.args) < 2 {
+		return fmt.Errorf("usage: %v <name> <url> [--basic user:password] [--bearer token] [--header Name:value]...", cmd.name)
+	}
+	auth, err := parseAuth(cmd.args[2:])
+	if err != nil {
+		return err
+	}
+	var credentials []byte
+	if auth != nil {
+		if credentials, err = sealAuth(s.cfg, auth); err != nil {
+			return err
+		}
+	}
+
+	var feedfollow database.CreateFeedFollowRow
+	err = s.db.InTx(context.Background(), func(q database.Querier) error {
+		feed, err := q.AddFeed(context.Background(), database.AddFeedParams{
+			ID:          uuid.New(),
+			CreatedAt:   time.Now().UTC(),
+			UpdatedAt:   time.Now().UTC(),
+			Name:        cmd.args[0],
+			Url:         cmd.args[1],
+			UserID:      user.ID,
+			Credentials: credentials,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add feed to db: %w", err)
+		}
+		feedfollow, err = q.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    user.ID,
+			FeedID:    feed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create feed follow: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
-		log.Fatalf("failed to create feed follow: %v\n", err)
+		return err
 	}
 	fmt.Printf("%v added and followed %v\n", feedfollow.UserName, feedfollow.FeedName)
 	return nil
 }
 
+// parseAuth reads the credential flags given to addfeed. It returns nil if
+// there are none.
+func parseAuth(args []string) (*rss.Auth, error) {
+	var auth *rss.Auth
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("missing value for %v", args[i])
+		}
+		if auth == nil {
+			auth = &rss.Auth{}
+		}
+		flag, value := args[i], args[i+1]
+		i++
+		switch flag {
+		case "--basic":
+			username, password, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("--basic expects user:password")
+			}
+			auth.Username, auth.Password = username, password
+		case "--bearer":
+			auth.Token = value
+		case "--header":
+			name, val, ok := strings.Cut(value, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("--header expects Name:value")
+			}
+			if auth.Headers == nil {
+				auth.Headers = map[string]string{}
+			}
+			auth.Headers[strings.TrimSpace(name)] = strings.TrimSpace(val)
+		default:
+			return nil, fmt.Errorf("unknown option %v", flag)
+		}
+	}
+	return auth, nil
+}
+
+func secretKey(cfg *config.Config) ([]byte, error) {
+	encoded := os.Getenv("GATOR_SECRET_KEY")
+	if encoded == "" {
+		encoded = cfg.Secret_key
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("no secret key for feed credentials, set secret_key in the config or GATOR_SECRET_KEY")
+	}
+	return secret.ParseKey(encoded)
+}
+
+func sealAuth(cfg *config.Config, auth *rss.Auth) ([]byte, error) {
+	key, err := secretKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed credentials: %w", err)
+	}
+	return secret.Seal(key, data)
+}
+
+// feedAuth decrypts a feed's stored credentials, or returns nil if it has
+// none.
+func feedAuth(cfg *config.Config, feed database.Feed) (*rss.Auth, error) {
+	if feed.Credentials == nil {
+		return nil, nil
+	}
+	key, err := secretKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	data, err := secret.Open(key, feed.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials of %v: %w", feed.Url, err)
+	}
+	var auth rss.Auth
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials of %v: %w", feed.Url, err)
+	}
+	return &auth, nil
+}
+
 func handlerFeeds(s *state, _ command) error {
 	feeds, err := s.db.Feeds(context.Background())
 	if err != nil {
-		log.Fatalf("failed to retrieve feeds from db: %v\n", err)
+		return fmt.Errorf("failed to retrieve feeds from db: %w", err)
 	}
 
 	if len(feeds) > 0 {
@@ -219,31 +903,67 @@ func handlerFeeds(s *state, _ command) error {
 	for i := range feeds {
 		user, err := s.db.GetUserByID(context.Background(), feeds[i].UserID)
 		if err != nil {
-			log.Fatalf("failed to retrieve user from db: %v\n", err)
+			return fmt.Errorf("failed to retrieve user from db: %w", err)
+		}
+		fmt.Printf("%v @ %v added by %v", feeds[i].Name, feeds[i].Url, user.Name)
+		if feeds[i].FetchFullContent {
+			fmt.Print(" (full content)")
+		}
+		if feeds[i].RetiredAt.Valid {
+			fmt.Print(" (retired)")
 		}
-		fmt.Printf("%v @ %v added by %v\n", feeds[i].Name, feeds[i].Url, user.Name)
+		if feeds[i].Credentials != nil {
+			fmt.Print(" (authenticated)")
+		}
+		fmt.Println()
+		if feeds[i].KeepPosts.Valid || feeds[i].KeepForSeconds.Valid {
+			fmt.Printf("  retention: %v\n", retentionPolicy{}.forFeed(feeds[i]))
+		}
+		for _, warning := range feeds[i].ParseWarnings {
+			fmt.Printf("  warning: %v\n", render.Clean(warning))
+		}
+	}
+	return nil
+}
+
+func handlerFullContent(s *state, cmd command) error {
+	if len(cmd.args) < 2 || (cmd.args[1] != "on" && cmd.args[1] != "off") {
+		return fmt.Errorf("usage: %v <url> <on|off>", cmd.name)
+	}
+
+	feed, err := s.db.GetFeedByURL(context.Background(), cmd.args[0])
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feed from db: %w", err)
+	}
+	if err := s.db.SetFeedFetchFullContent(context.Background(), database.SetFeedFetchFullContentParams{
+		ID:               feed.ID,
+		UpdatedAt:        time.Now().UTC(),
+		FetchFullContent: cmd.args[1] == "on",
+	}); err != nil {
+		return fmt.Errorf("failed to update feed: %w", err)
 	}
+	fmt.Printf("full content fetching %v for %v\n", cmd.args[1], feed.Name)
 	return nil
 }
 
 func handlerFollow(s *state, cmd command, user database.User) error {
 	if len(cmd.args) < 1 {
-		log.Fatalf("missing url for command %v\n", cmd.name)
+		return fmt.Errorf("missing url for command %v", cmd.name)
 	}
 
 	feed, err := s.db.GetFeedByURL(context.Background(), cmd.args[0])
 	if err != nil {
-		log.Fatalf("failed to retrieve feed from db: %v\n", err)
+		return fmt.Errorf("failed to retrieve feed from db: %w", err)
 	}
 	feedfollow, err := s.db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
 		UserID:    user.ID,
 		FeedID:    feed.ID,
 	})
 	if err != nil {
-		log.Fatalf("failed to create feed follow: %v\n", err)
+		return fmt.Errorf("failed to create feed follow: %w", err)
 	}
 	fmt.Printf("%v followed %v\n", feedfollow.UserName, feedfollow.FeedName)
 
@@ -253,7 +973,7 @@ func handlerFollow(s *state, cmd command, user database.User) error {
 func handlerFollowing(s *state, cmd command, user database.User) error {
 	follows, err := s.db.GetFeedFollowsForUser(context.Background(), user.ID)
 	if err != nil {
-		log.Fatalf("failed to retrieve feed follows from db: %v\n", err)
+		return fmt.Errorf("failed to retrieve feed follows from db: %w", err)
 	}
 	if len(follows) > 0 {
 		fmt.Printf("%v currently following:\n", user.Name)
@@ -261,7 +981,7 @@ func handlerFollowing(s *state, cmd command, user database.User) error {
 	for i := range follows {
 		feed, err := s.db.GetFeedByID(context.Background(), follows[i].FeedID)
 		if err != nil {
-			log.Fatalf("failed to retrieve feed from db: %v\n", err)
+			return fmt.Errorf("failed to retrieve feed from db: %w", err)
 		}
 		fmt.Printf("%v\n", feed.Name)
 	}
@@ -270,18 +990,18 @@ func handlerFollowing(s *state, cmd command, user database.User) error {
 
 func handlerUnfollow(s *state, cmd command, user database.User) error {
 	if len(cmd.args) < 1 {
-		log.Fatalf("missing url for command %v\n", cmd.name)
+		return fmt.Errorf("missing url for command %v", cmd.name)
 	}
 
 	feed, err := s.db.GetFeedByURL(context.Background(), cmd.args[0])
 	if err != nil {
-		log.Fatalf("failed to retrieve feed from db: %v\n", err)
+		return fmt.Errorf("failed to retrieve feed from db: %w", err)
 	}
 	if err := s.db.UnfollowFeed(context.Background(), database.UnfollowFeedParams{
 		UserID: user.ID,
 		FeedID: feed.ID,
 	}); err != nil {
-		log.Fatalf("failed to remove feed follow from db: %v\n", err)
+		return fmt.Errorf("failed to remove feed follow from db: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/config"
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/brendenwelch/gator/internal/database/sqlite"
+	"github.com/brendenwelch/gator/internal/migrate"
+	"github.com/brendenwelch/gator/sql/schema"
+	sqliteschema "github.com/brendenwelch/gator/sql/sqlite/schema"
+	_ "github.com/lib/pq"
+)
+
+const defaultPingTimeout = 5 * time.Second
+
+// openDB connects to the database in db_url and checks that it is reachable.
+// URLs starting with sqlite: name a SQLite database file, as in
+// sqlite:///home/me/gator.db or sqlite:gator.db, and anything else is handed
+// to the Postgres driver.
+func openDB(cfg *config.Config) (database.Store, *migrate.Migrator, error) {
+	var db *sql.DB
+	var store database.Store
+	var migrator *migrate.Migrator
+	var err error
+	if path, ok := strings.CutPrefix(cfg.Db_url, "sqlite:"); ok {
+		path = strings.TrimPrefix(path, "//")
+		if path == "" {
+			return nil, nil, fmt.Errorf("missing path in sqlite db_url")
+		}
+		if db, err = sqlite.Open(path); err != nil {
+			return nil, nil, err
+		}
+		if migrator, err = migrate.New(db, migrate.SQLite, sqliteschema.FS); err != nil {
+			return nil, nil, err
+		}
+		store = sqlite.NewStore(db)
+	} else {
+		dbURL := cfg.Db_url
+		if cfg.Db.Statement_timeout != "" {
+			timeout, err := time.ParseDuration(cfg.Db.Statement_timeout)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to parse db statement timeout: %w", err)
+			}
+			if dbURL, err = withStatementTimeout(dbURL, timeout); err != nil {
+				return nil, nil, err
+			}
+		}
+		if db, err = sql.Open("postgres", dbURL); err != nil {
+			return nil, nil, err
+		}
+		if migrator, err = migrate.New(db, migrate.Postgres, schema.FS); err != nil {
+			return nil, nil, err
+		}
+		store = database.NewStore(db)
+	}
+
+	if err := configurePool(db, cfg.Db); err != nil {
+		return nil, nil, err
+	}
+	return store, migrator, nil
+}
+
+// configurePool applies the pool settings and pings the database, so a bad
+// db_url is reported before any command runs.
+func configurePool(db *sql.DB, cfg config.DBConfig) error {
+	if cfg.Max_open_conns != 0 {
+		db.SetMaxOpenConns(cfg.Max_open_conns)
+	}
+	if cfg.Max_idle_conns != 0 {
+		db.SetMaxIdleConns(cfg.Max_idle_conns)
+	}
+	if cfg.Conn_max_lifetime != "" {
+		lifetime, err := time.ParseDuration(cfg.Conn_max_lifetime)
+		if err != nil {
+			return fmt.Errorf("failed to parse db connection lifetime: %w", err)
+		}
+		db.SetConnMaxLifetime(lifetime)
+	}
+
+	pingTimeout := defaultPingTimeout
+	if cfg.Ping_timeout != "" {
+		var err error
+		if pingTimeout, err = time.ParseDuration(cfg.Ping_timeout); err != nil {
+			return fmt.Errorf("failed to parse db ping timeout: %w", err)
+		}
+	}
+	if pingTimeout < 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return nil
+}
+
+// withStatementTimeout adds Postgres' statement_timeout setting to a
+// connection string, which lib/pq passes on to the server. Both URLs and
+// key=value strings are supported.
+func withStatementTimeout(dbURL string, timeout time.Duration) (string, error) {
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if !strings.HasPrefix(dbURL, "postgres://") && !strings.HasPrefix(dbURL, "postgresql://") {
+		return dbURL + " statement_timeout=" + ms, nil
+	}
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse db_url: %w", err)
+	}
+	query := u.Query()
+	query.Set("statement_timeout", ms)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,678 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/config"
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/brendenwelch/gator/internal/database/memory"
+	"github.com/brendenwelch/gator/internal/feedtest"
+	"github.com/brendenwelch/gator/internal/input"
+	"github.com/brendenwelch/gator/internal/secret"
+	"github.com/google/uuid"
+)
+
+// newTestState returns a state backed by an in-memory store, with the config
+// file kept in a temporary home directory.
+func newTestState(t *testing.T) *state {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{}
+	fetcher, err := newFetcher(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &state{db: memory.New(), cfg: cfg, fetcher: fetcher, stdin: input.New(strings.NewReader(""))}
+}
+
+// newBlogState returns a test state in which alice has added the blog
+// fixture and its posts have been scraped, along with the server it is
+// served from.
+func newBlogState(t *testing.T) (*state, *feedtest.Server) {
+	t.Helper()
+	server := feedtest.NewServer()
+	t.Cleanup(server.Close)
+	s := newTestState(t)
+	run(t, s, "register alice")
+	run(t, s, "addfeed blog "+server.FeedURL("rss.xml"))
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	return s, server
+}
+
+// run runs a command line through the registered commands and returns what
+// it printed.
+func run(t *testing.T, s *state, line string) string {
+	t.Helper()
+	fields := strings.Fields(line)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+	err = newCommands().run(s, command{name: fields[0], args: fields[1:]})
+	w.Close()
+	os.Stdout = stdout
+	output := <-out
+	if err != nil {
+		t.Fatalf("%v: %v", line, err)
+	}
+	return output
+}
+
+func TestRegisterAddFeedAggBrowse(t *testing.T) {
+	server := feedtest.NewServer()
+	defer server.Close()
+	s := newTestState(t)
+	ctx := context.Background()
+
+	if out := run(t, s, "register alice"); !strings.Contains(out, "alice has been registered") {
+		t.Errorf("register printed %q", out)
+	}
+	if s.cfg.Current_user_name != "alice" {
+		t.Errorf("current user is %q, want alice", s.cfg.Current_user_name)
+	}
+
+	run(t, s, "addfeed blog "+server.FeedURL("rss.xml"))
+	run(t, s, "addfeed atom "+server.FeedURL("atom.xml"))
+	if out := run(t, s, "following"); !strings.Contains(out, "blog") || !strings.Contains(out, "atom") {
+		t.Errorf("following printed %q", out)
+	}
+
+	// Each scrape fetches the feed that has waited longest.
+	for range 2 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{"/rss.xml", "/atom.xml"} {
+		if n := server.Requests(path); n != 1 {
+			t.Errorf("%v requested %v times, want 1", path, n)
+		}
+	}
+
+	s.cfg.Timezone = "America/New_York"
+	out := run(t, s, "browse 10")
+	for _, want := range []string{
+		"Hello, world",
+		"https://blog.example.com/hello-world",
+		"  published Mon, 05 Jan 2026 04:30:00 EST",
+		"  by Ada",
+		"  categories: news, meta",
+		"Episode 2",
+		"  comments: https://blog.example.com/episode-2#comments",
+		"  enclosure: https://cdn.example.com/episode-2.mp3 (audio/mpeg, 1.0 MB)",
+		"Atom entry one",
+		"Atom entry two",
+		"  published Thu, 08 Jan 2026 07:00:00 EST",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("browse output is missing %q:\n%v", want, out)
+		}
+	}
+
+	for _, limit := range []string{"0", "-1", "abc", "10x"} {
+		if err := newCommands().run(s, command{name: "browse", args: []string{limit}}); err == nil {
+			t.Errorf("browse %v succeeded", limit)
+		}
+	}
+
+	post, err := s.db.GetPostByURL(ctx, "https://blog.example.com/hello-world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !post.Content.Valid || strings.Contains(post.Content.String, "<script") {
+		t.Errorf("content not sanitized: %q", post.Content.String)
+	}
+
+	run(t, s, "read https://blog.example.com/hello-world")
+	user, err := s.db.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeds, err := s.db.GetFeedsWithUnreadCount(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unread := map[string]int64{}
+	for _, feed := range feeds {
+		unread[feed.Name] = feed.Unread
+	}
+	if unread["blog"] != 1 || unread["atom"] != 2 {
+		t.Errorf("unread counts %v, want blog 1 and atom 2", unread)
+	}
+
+	// Fetching again must not duplicate posts.
+	for range 2 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	posts, err := s.db.GetPostsByUser(ctx, database.GetPostsByUserParams{Name: "alice", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 4 {
+		t.Errorf("got %v posts after fetching twice, want 4", len(posts))
+	}
+}
+
+func TestScrapeFeedsSkipsBrokenFeeds(t *testing.T) {
+	server := feedtest.NewServer()
+	defer server.Close()
+	s := newTestState(t)
+	s.cfg.Agg.Concurrency = 2
+
+	run(t, s, "register alice")
+	run(t, s, "addfeed missing "+server.FeedURL("missing.xml"))
+	run(t, s, "addfeed blog "+server.FeedURL("rss.xml"))
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatalf("a broken feed failed the whole scrape: %v", err)
+	}
+	if _, err := s.db.GetPostByURL(context.Background(), "https://blog.example.com/hello-world"); err != nil {
+		t.Errorf("the working feed was not scraped: %v", err)
+	}
+}
+
+func TestScrapeFeedDropsNonWebLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss version="2.0"><channel><title>Links</title>
+<item><title>Web</title><link>https://blog.example.com/web</link></item>
+<item><title>Script</title><link>javascript:alert(1)</link></item>
+<item><title>File</title><link>file:///home/alice/x.desktop</link></item>
+<item><title>Share</title><link>smb://attacker.example.com/share</link></item>
+<item><title>Option</title><link>-new-window</link></item>
+</channel></rss>`)
+	}))
+	defer server.Close()
+	s := newTestState(t)
+	run(t, s, "register alice")
+	run(t, s, "addfeed links "+server.URL)
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	posts, err := s.db.GetPostsByUser(context.Background(), database.GetPostsByUserParams{Name: "alice", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || posts[0].Url != "https://blog.example.com/web" {
+		t.Errorf("got posts %v, want only the web link", posts)
+	}
+}
+
+func TestMovedFeedKeepsCredentialsOnItsHost(t *testing.T) {
+	var leaked atomic.Bool
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Api-Key") != "" {
+			leaked.Store(true)
+		}
+		fmt.Fprint(w, `<rss version="2.0"><channel><title>Private</title></channel></rss>`)
+	}))
+	defer other.Close()
+	private := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// net/http only compares host names, so use another one to get
+		// Authorization dropped on the redirect.
+		http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1)+"/feed.xml", http.StatusMovedPermanently)
+	}))
+	defer private.Close()
+
+	s := newTestState(t)
+	s.cfg.Secret_key = base64.StdEncoding.EncodeToString(make([]byte, secret.KeySize))
+	run(t, s, "register alice")
+	run(t, s, "addfeed private "+private.URL+"/feed.xml --bearer token --header X-Api-Key:key")
+	for range 2 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if leaked.Load() {
+		t.Error("credentials were sent to the host the feed moved to")
+	}
+	feed, err := s.db.GetFeedByURL(context.Background(), private.URL+"/feed.xml")
+	if err != nil {
+		t.Fatalf("the feed's url was changed: %v", err)
+	}
+	if feed.Credentials == nil {
+		t.Error("the feed lost its credentials")
+	}
+}
+
+// newMovingServer serves a feed with one post at /old and another at /new,
+// and redirects /old to /new with status once moved is set.
+func newMovingServer(t *testing.T, status int, moved *atomic.Bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" && moved.Load() {
+			http.Redirect(w, r, "/new", status)
+			return
+		}
+		fmt.Fprintf(w, `<rss version="2.0"><channel><title>Moving</title>
+<item><title>Post at %[1]v</title><link>https://blog.example.com%[1]v-post</link></item>
+</channel></rss>`, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPermanentRedirectMovesFeed(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusPermanentRedirect} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			var moved atomic.Bool
+			server := newMovingServer(t, status, &moved)
+			s := newTestState(t)
+			ctx := context.Background()
+			run(t, s, "register alice")
+			run(t, s, "addfeed moving "+server.URL+"/old")
+
+			moved.Store(true)
+			if err := scrapeFeeds(s); err != nil {
+				t.Fatal(err)
+			}
+			feed, err := s.db.GetFeedByURL(ctx, server.URL+"/new")
+			if err != nil {
+				t.Fatalf("the feed was not moved: %v", err)
+			}
+			if feed.Name != "moving" {
+				t.Errorf("moved feed is called %q, want moving", feed.Name)
+			}
+			if _, err := s.db.GetPostByURL(ctx, "https://blog.example.com/new-post"); err != nil {
+				t.Errorf("the post at the new url was not stored: %v", err)
+			}
+		})
+	}
+}
+
+func TestPermanentRedirectMergesIntoExistingFeed(t *testing.T) {
+	var moved atomic.Bool
+	server := newMovingServer(t, http.StatusMovedPermanently, &moved)
+	s := newTestState(t)
+	ctx := context.Background()
+
+	run(t, s, "register alice")
+	run(t, s, "addfeed old "+server.URL+"/old")
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	run(t, s, "register bob")
+	run(t, s, "addfeed new "+server.URL+"/new")
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+
+	moved.Store(true)
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.GetFeedByURL(ctx, server.URL+"/old"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("the old feed was not deleted: %v", err)
+	}
+	into, err := s.db.GetFeedByURL(ctx, server.URL+"/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := s.db.GetPostByURL(ctx, "https://blog.example.com/old-post")
+	if err != nil {
+		t.Fatalf("the old feed's post was lost: %v", err)
+	}
+	if post.FeedID != into.ID {
+		t.Error("the old feed's post was not moved to the feed it merged into")
+	}
+	run(t, s, "login alice")
+	if out := run(t, s, "following"); !strings.Contains(out, "new") || strings.Contains(out, "old") {
+		t.Errorf("alice's follows were not merged, following printed %q", out)
+	}
+}
+
+func TestGoneRetiresFeed(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+	s := newTestState(t)
+	run(t, s, "register alice")
+	run(t, s, "addfeed gone "+server.URL)
+
+	for range 2 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	feed, err := s.db.GetFeedByURL(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !feed.RetiredAt.Valid {
+		t.Error("the feed was not retired")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("the feed was requested %v times, want it left alone once retired", n)
+	}
+	if out := run(t, s, "feeds"); !strings.Contains(out, "gone @ "+server.URL+" added by alice (retired)") {
+		t.Errorf("feeds printed %q", out)
+	}
+}
+
+func TestFeedsCleansParseWarnings(t *testing.T) {
+	s, server := newBlogState(t)
+	ctx := context.Background()
+	feed, err := s.db.GetFeedByURL(ctx, server.FeedURL("rss.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.SetFeedParseWarnings(ctx, database.SetFeedParseWarningsParams{
+		ID:            feed.ID,
+		UpdatedAt:     time.Now().UTC(),
+		ParseWarnings: []string{"not well-formed: \x1b]0;pwned\x07bad \x1b[2Jentity"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	out := run(t, s, "feeds")
+	if strings.ContainsAny(out, "\x1b\x07") {
+		t.Errorf("feeds printed control characters: %q", out)
+	}
+	if !strings.Contains(out, "  warning: not well-formed: ]0;pwnedbad [2Jentity") {
+		t.Errorf("feeds printed %q", out)
+	}
+}
+
+func TestAllTransient(t *testing.T) {
+	transient := fmt.Errorf("failed to mark feed fetched: %w", driver.ErrBadConn)
+	permanent := errors.New("constraint violated")
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{transient, true},
+		{permanent, false},
+		{errors.Join(transient, transient), true},
+		{errors.Join(transient, permanent), false},
+		{errors.Join(nil, transient), true},
+	}
+	for _, tt := range tests {
+		if got := allTransient(tt.err); got != tt.want {
+			t.Errorf("allTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestEnclosureFilename(t *testing.T) {
+	day := time.Date(2026, 1, 6, 9, 30, 0, 0, time.UTC)
+	seen := map[string]bool{}
+	for _, rawURL := range []string{
+		"https://cdn.example.com/show-a/episode.mp3",
+		"https://cdn.example.com/show-b/episode.mp3",
+		"https://cdn.example.com/download",
+		"https://cdn.example.com/download?id=2",
+	} {
+		name := enclosureFilename(database.GetEnclosuresForFeedRow{ID: uuid.New(), Url: rawURL, PublishedAt: day})
+		if !strings.HasPrefix(name, "2026-01-06-") {
+			t.Errorf("%v is not prefixed with its publication date", name)
+		}
+		if seen[name] {
+			t.Errorf("%v is used by two enclosures from the same day", name)
+		}
+		seen[name] = true
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s, server := newBlogState(t)
+	ctx := context.Background()
+
+	if out := run(t, s, "retention "+server.FeedURL("rss.xml")+" keep 1"); !strings.Contains(out, "keep at most 1 posts") {
+		t.Errorf("retention printed %q", out)
+	}
+
+	out := run(t, s, "prune --dry-run")
+	if !strings.Contains(out, "would remove 1 posts from blog") || !strings.Contains(out, "Hello, world") {
+		t.Errorf("prune --dry-run printed %q", out)
+	}
+
+	user, err := s.db.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := s.db.GetPostByURL(ctx, "https://blog.example.com/hello-world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	star := func(starred bool) {
+		if err := s.db.SetPostStarred(ctx, database.SetPostStarredParams{
+			ID:      uuid.New(),
+			UserID:  user.ID,
+			PostID:  post.ID,
+			Starred: starred,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	star(true)
+	if out := run(t, s, "prune"); !strings.Contains(out, "nothing to prune") {
+		t.Errorf("prune removed a starred post: %q", out)
+	}
+
+	star(false)
+	if out := run(t, s, "prune"); !strings.Contains(out, "removed 1 posts from blog") {
+		t.Errorf("prune printed %q", out)
+	}
+	if _, err := s.db.GetPostByURL(ctx, post.Url); err == nil {
+		t.Errorf("%v was not pruned", post.Url)
+	}
+	if _, err := s.db.GetPostByURL(ctx, "https://blog.example.com/episode-2"); err != nil {
+		t.Errorf("newest post was pruned: %v", err)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	s, _ := newBlogState(t)
+	ctx := context.Background()
+
+	run(t, s, "read https://blog.example.com/hello-world")
+	alice, err := s.db.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := t.TempDir() + "/gator.ndjson"
+	run(t, s, "backup "+path)
+
+	// The new instance already has a user with alice's id.
+	restored := newTestState(t)
+	if _, err := restored.db.CreateUser(ctx, database.CreateUserParams{ID: alice.ID, Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if out := run(t, restored, "restore "+path); !strings.Contains(out, "restored 1 users, 1 feeds, 1 follows, 2 posts and 1 post states") {
+		t.Errorf("restore printed %q", out)
+	}
+	user, err := restored.db.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID == alice.ID {
+		t.Errorf("restored alice kept an id that was taken")
+	}
+	feeds, err := restored.db.GetFeedsWithUnreadCount(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feeds) != 1 || feeds[0].Name != "blog" || feeds[0].Unread != 1 {
+		t.Errorf("restored feeds %+v, want blog with 1 unread post", feeds)
+	}
+	enclosures, err := restored.db.GetEnclosuresForFeed(ctx, feeds[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enclosures) != 1 {
+		t.Errorf("restored %v enclosures, want 1", len(enclosures))
+	}
+
+	// Restoring again adds nothing and leaves later changes alone.
+	post, err := restored.db.GetPostByURL(ctx, "https://blog.example.com/hello-world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.db.SetPostRead(ctx, database.SetPostReadParams{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		PostID: post.ID,
+		Read:   false,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	out := run(t, restored, "restore "+path)
+	if !strings.Contains(out, "restored 0 users, 0 feeds, 0 follows, 0 posts and 0 post states") || !strings.Contains(out, "6 records were already present") {
+		t.Errorf("second restore printed %q", out)
+	}
+	feeds, err = restored.db.GetFeedsWithUnreadCount(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feeds[0].Unread != 2 {
+		t.Errorf("restore marked a post read again, %v unread, want 2", feeds[0].Unread)
+	}
+}
+
+func TestResetScopes(t *testing.T) {
+	s, server := newBlogState(t)
+	ctx := context.Background()
+
+	user, err := s.db.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run(t, s, "reset --fetch-state --yes")
+	feed, err := s.db.GetFeedByURL(ctx, server.FeedURL("rss.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.LastFetchedAt.Valid {
+		t.Errorf("fetch state reset kept last fetched time %v", feed.LastFetchedAt.Time)
+	}
+
+	// Without a terminal to ask on, only --yes will do.
+	err = newCommands().run(s, command{name: "reset", args: []string{"--posts"}})
+	if err == nil || !strings.Contains(err.Error(), "without --yes") {
+		t.Errorf("reset without --yes or a terminal returned %v", err)
+	}
+	if posts, err := s.db.GetPostsByFeedID(ctx, feed.ID); err != nil || len(posts) == 0 {
+		t.Errorf("unconfirmed reset removed posts: %v, %v", posts, err)
+	}
+
+	run(t, s, "reset --posts --yes")
+	posts, err := s.db.GetPostsByFeedID(ctx, feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("%v posts left after reset --posts", len(posts))
+	}
+
+	run(t, s, "reset --user alice --yes")
+	follows, err := s.db.GetFeedFollowsForUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(follows) != 0 {
+		t.Errorf("%v follows left after reset --user", len(follows))
+	}
+	if _, err := s.db.GetFeedByID(ctx, feed.ID); err != nil {
+		t.Errorf("reset --user removed the feed: %v", err)
+	}
+
+	path := t.TempDir() + "/before-reset.ndjson"
+	if out := run(t, s, "reset --yes --backup "+path); !strings.Contains(out, "backup written to "+path) {
+		t.Errorf("reset printed %q", out)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+	if users, err := s.db.GetUsers(ctx); err != nil || len(users) != 0 {
+		t.Errorf("users after reset: %v, %v", users, err)
+	}
+}
+
+func TestUserManagement(t *testing.T) {
+	s, server := newBlogState(t)
+	ctx := context.Background()
+
+	run(t, s, "addfeed atom "+server.FeedURL("atom.xml"))
+	run(t, s, "register bob")
+	run(t, s, "follow "+server.FeedURL("rss.xml"))
+	run(t, s, "register carol")
+	run(t, s, "login bob")
+
+	run(t, s, "profile display_name Bob Smith")
+	run(t, s, "profile timezone Europe/Berlin")
+	out := run(t, s, "profile browse_limit 1")
+	for _, want := range []string{"display name: Bob Smith", "timezone: Europe/Berlin", "browse limit: 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("profile output is missing %q:\n%v", want, out)
+		}
+	}
+
+	run(t, s, "renameuser bob robert")
+	if s.cfg.Current_user_name != "robert" {
+		t.Errorf("current user is %q after rename, want robert", s.cfg.Current_user_name)
+	}
+	if out := run(t, s, "users"); !strings.Contains(out, "* robert (Bob Smith) (current)") {
+		t.Errorf("users printed %q", out)
+	}
+
+	// robert follows blog and inherits it; nobody follows atom, so it goes to
+	// carol only when she is named.
+	out = run(t, s, "deluser alice --to carol --yes")
+	if !strings.Contains(out, "blog now belongs to carol") || !strings.Contains(out, "atom now belongs to carol") {
+		t.Errorf("deluser --to printed %q", out)
+	}
+	out = run(t, s, "deluser carol --yes")
+	if !strings.Contains(out, "blog now belongs to robert") || !strings.Contains(out, "removed atom") {
+		t.Errorf("deluser printed %q", out)
+	}
+	robert, err := s.db.GetUser(ctx, "robert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := s.db.GetFeedByURL(ctx, server.FeedURL("rss.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.UserID != robert.ID {
+		t.Errorf("blog is owned by %v, want robert", feed.UserID)
+	}
+	if _, err := s.db.GetFeedByURL(ctx, server.FeedURL("atom.xml")); err == nil {
+		t.Errorf("atom survived the deletion of its only owner")
+	}
+
+	out = run(t, s, "browse")
+	if n := strings.Count(out, "  published "); n != 1 || !strings.Contains(out, "CET") {
+		t.Errorf("browse with a browse limit of 1 printed %q", out)
+	}
+
+	run(t, s, "deluser robert --yes")
+	if s.cfg.Current_user_name != "" {
+		t.Errorf("current user is %q after deleting it", s.cfg.Current_user_name)
+	}
+}
@@ -0,0 +1,151 @@
+// Package backup reads and writes gator's portable archive format. An
+// archive is newline delimited JSON: a header line naming the format and its
+// version, then one record per line. Records refer to each other by the ids
+// they had when the archive was written, and every record comes after the
+// ones it refers to.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Format  = "gator-backup"
+	Version = 1
+)
+
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Record holds exactly one of its fields.
+type Record struct {
+	User      *User      `json:"user,omitempty"`
+	Feed      *Feed      `json:"feed,omitempty"`
+	Follow    *Follow    `json:"follow,omitempty"`
+	Post      *Post      `json:"post,omitempty"`
+	PostState *PostState `json:"post_state,omitempty"`
+}
+
+type User struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+	BrowseLimit int32     `json:"browse_limit,omitempty"`
+}
+
+// Feed leaves out credentials, which are sealed with the secret key of the
+// instance that stored them.
+type Feed struct {
+	ID               uuid.UUID  `json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	Name             string     `json:"name"`
+	URL              string     `json:"url"`
+	UserID           uuid.UUID  `json:"user_id"`
+	FetchFullContent bool       `json:"fetch_full_content,omitempty"`
+	RetiredAt        *time.Time `json:"retired_at,omitempty"`
+	KeepPosts        *int32     `json:"keep_posts,omitempty"`
+	KeepForSeconds   *int64     `json:"keep_for_seconds,omitempty"`
+}
+
+type Follow struct {
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	FeedID    uuid.UUID `json:"feed_id"`
+}
+
+type Post struct {
+	ID          uuid.UUID   `json:"id"`
+	CreatedAt   time.Time   `json:"created_at"`
+	FeedID      uuid.UUID   `json:"feed_id"`
+	Title       string      `json:"title"`
+	URL         string      `json:"url"`
+	Description string      `json:"description,omitempty"`
+	PublishedAt time.Time   `json:"published_at"`
+	Content     *string     `json:"content,omitempty"`
+	Authors     []string    `json:"authors,omitempty"`
+	Categories  []string    `json:"categories,omitempty"`
+	CommentsURL string      `json:"comments_url,omitempty"`
+	ImageURL    string      `json:"image_url,omitempty"`
+	Enclosures  []Enclosure `json:"enclosures,omitempty"`
+}
+
+type Enclosure struct {
+	URL      string `json:"url"`
+	Length   int64  `json:"length,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	Duration string `json:"duration,omitempty"`
+	Episode  *int32 `json:"episode,omitempty"`
+	Season   *int32 `json:"season,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type PostState struct {
+	UserID  uuid.UUID `json:"user_id"`
+	PostID  uuid.UUID `json:"post_id"`
+	Read    bool      `json:"read,omitempty"`
+	Starred bool      `json:"starred,omitempty"`
+}
+
+type Writer struct {
+	enc *json.Encoder
+}
+
+// NewWriter starts an archive on w by writing its header.
+func NewWriter(w io.Writer, createdAt time.Time) (*Writer, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(Header{Format: Format, Version: Version, CreatedAt: createdAt}); err != nil {
+		return nil, err
+	}
+	return &Writer{enc: enc}, nil
+}
+
+func (w *Writer) Write(record Record) error {
+	return w.enc.Encode(record)
+}
+
+type Reader struct {
+	Header Header
+	dec    *json.Decoder
+}
+
+// NewReader reads the header of the archive in r and checks that this
+// version of gator understands it.
+func NewReader(r io.Reader) (*Reader, error) {
+	dec := json.NewDecoder(r)
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read backup header: %w", err)
+	}
+	if header.Format != Format {
+		return nil, fmt.Errorf("not a gator backup")
+	}
+	if header.Version < 1 || header.Version > Version {
+		return nil, fmt.Errorf("backup version %v is not supported, this gator reads up to version %v", header.Version, Version)
+	}
+	return &Reader{Header: header, dec: dec}, nil
+}
+
+// Next returns the next record, or io.EOF after the last one.
+func (r *Reader) Next() (Record, error) {
+	var record Record
+	if err := r.dec.Decode(&record); err != nil {
+		if errors.Is(err, io.EOF) {
+			return record, io.EOF
+		}
+		return record, fmt.Errorf("failed to read backup record: %w", err)
+	}
+	return record, nil
+}
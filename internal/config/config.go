@@ -2,12 +2,102 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"time"
 )
 
 type Config struct {
-	Db_url            string `json:"db_url"`
-	Current_user_name string `json:"current_user_name"`
+	Db_url            string          `json:"db_url"`
+	Db                DBConfig        `json:"db,omitzero"`
+	Current_user_name string          `json:"current_user_name"`
+	Sanitizer         SanitizerConfig `json:"sanitizer,omitzero"`
+	Download_dir      string          `json:"download_dir,omitempty"`
+	Download_keep     int             `json:"download_keep,omitempty"`
+	Fetch             FetchConfig     `json:"fetch,omitzero"`
+	Agg               AggConfig       `json:"agg,omitzero"`
+	Retention         RetentionConfig `json:"retention,omitzero"`
+	// Secret_key encrypts feed credentials in the database. It is a base64
+	// AES-256 key and the GATOR_SECRET_KEY environment variable overrides it.
+	Secret_key string `json:"secret_key,omitempty"`
+	// Timezone is the IANA zone, like "Europe/Berlin", that dates are shown
+	// in. The system's local zone is used if unset.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// DBConfig tunes the database connection pool. Durations are strings like
+// "5m" and zero values keep database/sql's defaults.
+type DBConfig struct {
+	Max_open_conns    int    `json:"max_open_conns,omitempty"`
+	Max_idle_conns    int    `json:"max_idle_conns,omitempty"`
+	Conn_max_lifetime string `json:"conn_max_lifetime,omitempty"`
+	// Statement_timeout has Postgres cancel statements that run longer. It
+	// is ignored for SQLite.
+	Statement_timeout string `json:"statement_timeout,omitempty"`
+	// Ping_timeout bounds the connection check made at startup, 5s if unset.
+	// A negative duration skips the check.
+	Ping_timeout string `json:"ping_timeout,omitempty"`
+}
+
+// AggConfig tunes the aggregator. Concurrency is how many feeds are fetched
+// at once each cycle, one if unset. Retries is how many times in a row a
+// transient database error is retried before agg gives up, 10 if unset and
+// none if negative, waiting twice as long each time up to Max_backoff.
+type AggConfig struct {
+	Concurrency int    `json:"concurrency,omitempty"`
+	Retries     int    `json:"retries,omitempty"`
+	Max_backoff string `json:"max_backoff,omitempty"`
+}
+
+// RetentionConfig is the default policy for which posts prune removes, which
+// the retention command can override per feed. Posts beyond the newest
+// Keep_posts of a feed, or published longer than Keep_for (like "720h") ago,
+// are removed. Zero values keep everything. Starred posts are always kept, as
+// are posts a follower of the feed hasn't read if Keep_unread is set.
+// Prune_after_agg has agg prune after every cycle.
+type RetentionConfig struct {
+	Keep_posts      int    `json:"keep_posts,omitempty"`
+	Keep_for        string `json:"keep_for,omitempty"`
+	Keep_unread     bool   `json:"keep_unread,omitempty"`
+	Prune_after_agg bool   `json:"prune_after_agg,omitempty"`
+}
+
+// FetchConfig sets up the HTTP client used to fetch feeds. Durations are
+// strings like "30s" and zero values keep the defaults.
+type FetchConfig struct {
+	Timeout         string `json:"timeout,omitempty"`
+	Connect_timeout string `json:"connect_timeout,omitempty"`
+	Max_body_size   int64  `json:"max_body_size,omitempty"`
+	User_agent      string `json:"user_agent,omitempty"`
+	Contact_url     string `json:"contact_url,omitempty"`
+	Proxy_url       string `json:"proxy_url,omitempty"`
+	Ca_bundle       string `json:"ca_bundle,omitempty"`
+	Max_redirects   int    `json:"max_redirects,omitempty"`
+	// Politeness limits per host: requests per second, how many of those may
+	// be sent back to back, and how many may be in flight at once. Negative
+	// values turn a limit off.
+	Host_rate        float64 `json:"host_rate,omitempty"`
+	Host_burst       int     `json:"host_burst,omitempty"`
+	Host_concurrency int     `json:"host_concurrency,omitempty"`
+}
+
+// SanitizerConfig adjusts the default policy used to clean post HTML.
+type SanitizerConfig struct {
+	Allow_elements       map[string][]string `json:"allow_elements,omitempty"`
+	Url_schemes          []string            `json:"url_schemes,omitempty"`
+	Keep_tracking_images bool                `json:"keep_tracking_images,omitempty"`
+}
+
+// Location loads the zone dates are displayed in.
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
 }
 
 func (c *Config) SetUser(user string) error {
@@ -43,7 +133,12 @@ func Write(cfg *Config) error {
 	if err != nil {
 		return err
 	}
-	if err = os.WriteFile(path, data, 0777); err != nil {
+	// The file holds the secret key, so keep it private to the user. WriteFile
+	// only applies the mode to new files.
+	if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = os.WriteFile(path, data, 0600); err != nil {
 		return err
 	}
 	return nil
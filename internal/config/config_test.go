@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteKeepsConfigPrivate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".gatorconfig.json")
+	if err := os.WriteFile(path, []byte(`{"db_url":"postgres://localhost/gator"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetUser("alice"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("config file mode is %v, want 0600", mode)
+	}
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+
+	"github.com/lib/pq"
+)
+
+// IsTransient reports whether err looks like a database error that may go
+// away if the operation is retried, such as a dropped connection, the server
+// restarting or a lock conflict. Errors from HTTP requests are not counted,
+// even though they may have the same network causes.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03", // cannot_connect_now
+			"53300", // too_many_connections
+			"40001", // serialization_failure
+			"40P01": // deadlock_detected
+			return true
+		}
+		// Class 08 is connection exceptions.
+		return pqErr.Code.Class() == "08"
+	}
+	// SQLite reports SQLITE_BUSY (5) and SQLITE_LOCKED (6), possibly
+	// extended, when the busy timeout runs out.
+	var codeErr interface{ Code() int }
+	if errors.As(err, &codeErr) {
+		code := codeErr.Code() & 0xff
+		return code == 5 || code == 6
+	}
+	var netErr *net.OpError
+	return errors.As(err, &netErr)
+}
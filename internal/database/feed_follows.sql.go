@@ -71,6 +71,48 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 	return i, err
 }
 
+const deleteFeedFollowsForUser = `-- name: DeleteFeedFollowsForUser :exec
+DELETE FROM feed_follows WHERE user_id = $1
+`
+
+func (q *Queries) DeleteFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedFollowsForUser, userID)
+	return err
+}
+
+const getFeedFollowsForFeed = `-- name: GetFeedFollowsForFeed :many
+SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows WHERE feed_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetFeedFollowsForFeed(ctx context.Context, feedID uuid.UUID) ([]FeedFollow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedFollow
+	for rows.Next() {
+		var i FeedFollow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows WHERE user_id = $1
 `
@@ -104,6 +146,25 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
+const mergeFeedFollows = `-- name: MergeFeedFollows :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+    SELECT gen_random_uuid(), feed_follows.created_at, $1::TIMESTAMPTZ, feed_follows.user_id, $2::UUID
+    FROM feed_follows
+    WHERE feed_follows.feed_id = $3
+    ON CONFLICT (user_id, feed_id) DO NOTHING
+`
+
+type MergeFeedFollowsParams struct {
+	UpdatedAt  time.Time
+	IntoFeedID uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MergeFeedFollows(ctx context.Context, arg MergeFeedFollowsParams) error {
+	_, err := q.db.ExecContext(ctx, mergeFeedFollows, arg.UpdatedAt, arg.IntoFeedID, arg.FromFeedID)
+	return err
+}
+
 const unfollowFeed = `-- name: UnfollowFeed :exec
 DELETE FROM feed_follows WHERE user_id = $1 AND feed_id = $2
 `
@@ -11,28 +11,31 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const addFeed = `-- name: AddFeed :one
-INSERT INTO feeds(id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds(id, created_at, updated_at, name, url, user_id, credentials)
   VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7
   )
-  RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+  RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds
 `
 
 type AddFeedParams struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Name      string
-	Url       string
-	UserID    uuid.UUID
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Url         string
+	UserID      uuid.UUID
+	Credentials []byte
 }
 
 func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error) {
@@ -43,6 +46,7 @@ func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error)
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.Credentials,
 	)
 	var i Feed
 	err := row.Scan(
@@ -53,12 +57,37 @@ func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error)
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		pq.Array(&i.ParseWarnings),
+		&i.RetiredAt,
+		&i.NextFetchAt,
+		&i.Credentials,
+		&i.KeepPosts,
+		&i.KeepForSeconds,
 	)
 	return i, err
 }
 
+const deleteAllFeeds = `-- name: DeleteAllFeeds :exec
+DELETE FROM feeds
+`
+
+func (q *Queries) DeleteAllFeeds(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteAllFeeds)
+	return err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
 const feeds = `-- name: Feeds :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds
 `
 
 func (q *Queries) Feeds(ctx context.Context) ([]Feed, error) {
@@ -78,6 +107,13 @@ func (q *Queries) Feeds(ctx context.Context) ([]Feed, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.FetchFullContent,
+			pq.Array(&i.ParseWarnings),
+			&i.RetiredAt,
+			&i.NextFetchAt,
+			&i.Credentials,
+			&i.KeepPosts,
+			&i.KeepForSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -93,7 +129,7 @@ func (q *Queries) Feeds(ctx context.Context) ([]Feed, error) {
 }
 
 const getFeedByID = `-- name: GetFeedByID :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds WHERE id = $1
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds WHERE id = $1
 `
 
 func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
@@ -107,12 +143,19 @@ func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		pq.Array(&i.ParseWarnings),
+		&i.RetiredAt,
+		&i.NextFetchAt,
+		&i.Credentials,
+		&i.KeepPosts,
+		&i.KeepForSeconds,
 	)
 	return i, err
 }
 
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds WHERE url = $1
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds WHERE url = $1
 `
 
 func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
@@ -126,28 +169,65 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		pq.Array(&i.ParseWarnings),
+		&i.RetiredAt,
+		&i.NextFetchAt,
+		&i.Credentials,
+		&i.KeepPosts,
+		&i.KeepForSeconds,
 	)
 	return i, err
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
-    ORDER BY last_fetched_at ASC NULLS FIRST LIMIT 1
+const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds
+    WHERE retired_at IS NULL
+    AND (next_fetch_at IS NULL OR next_fetch_at <= $1::TIMESTAMPTZ)
+    ORDER BY last_fetched_at ASC NULLS FIRST LIMIT $2
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-		&i.LastFetchedAt,
-	)
-	return i, err
+type GetNextFeedsToFetchParams struct {
+	Now      time.Time
+	MaxFeeds int32
+}
+
+func (q *Queries) GetNextFeedsToFetch(ctx context.Context, arg GetNextFeedsToFetchParams) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getNextFeedsToFetch, arg.Now, arg.MaxFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.FetchFullContent,
+			pq.Array(&i.ParseWarnings),
+			&i.RetiredAt,
+			&i.NextFetchAt,
+			&i.Credentials,
+			&i.KeepPosts,
+			&i.KeepForSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const markFeedFetched = `-- name: MarkFeedFetched :exec
@@ -168,3 +248,173 @@ func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams
 	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.ID, arg.UpdatedAt, arg.LastFetchedAt)
 	return err
 }
+
+const mergeFeedPosts = `-- name: MergeFeedPosts :exec
+UPDATE posts
+    SET feed_id = $1
+    WHERE feed_id = $2
+`
+
+type MergeFeedPostsParams struct {
+	IntoFeedID uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MergeFeedPosts(ctx context.Context, arg MergeFeedPostsParams) error {
+	_, err := q.db.ExecContext(ctx, mergeFeedPosts, arg.IntoFeedID, arg.FromFeedID)
+	return err
+}
+
+const resetFeedFetchState = `-- name: ResetFeedFetchState :exec
+UPDATE feeds
+    SET
+	updated_at = $1,
+	last_fetched_at = NULL,
+	next_fetch_at = NULL,
+	parse_warnings = '{}'
+`
+
+func (q *Queries) ResetFeedFetchState(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, resetFeedFetchState, updatedAt)
+	return err
+}
+
+const retireFeed = `-- name: RetireFeed :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	retired_at = $3
+    WHERE id = $1
+`
+
+type RetireFeedParams struct {
+	ID        uuid.UUID
+	UpdatedAt time.Time
+	RetiredAt sql.NullTime
+}
+
+func (q *Queries) RetireFeed(ctx context.Context, arg RetireFeedParams) error {
+	_, err := q.db.ExecContext(ctx, retireFeed, arg.ID, arg.UpdatedAt, arg.RetiredAt)
+	return err
+}
+
+const setFeedFetchFullContent = `-- name: SetFeedFetchFullContent :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	fetch_full_content = $3
+    WHERE id = $1
+`
+
+type SetFeedFetchFullContentParams struct {
+	ID               uuid.UUID
+	UpdatedAt        time.Time
+	FetchFullContent bool
+}
+
+func (q *Queries) SetFeedFetchFullContent(ctx context.Context, arg SetFeedFetchFullContentParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchFullContent, arg.ID, arg.UpdatedAt, arg.FetchFullContent)
+	return err
+}
+
+const setFeedNextFetch = `-- name: SetFeedNextFetch :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	next_fetch_at = $3
+    WHERE id = $1
+`
+
+type SetFeedNextFetchParams struct {
+	ID          uuid.UUID
+	UpdatedAt   time.Time
+	NextFetchAt sql.NullTime
+}
+
+func (q *Queries) SetFeedNextFetch(ctx context.Context, arg SetFeedNextFetchParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNextFetch, arg.ID, arg.UpdatedAt, arg.NextFetchAt)
+	return err
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	user_id = $3
+    WHERE id = $1
+`
+
+type SetFeedOwnerParams struct {
+	ID        uuid.UUID
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedOwner, arg.ID, arg.UpdatedAt, arg.UserID)
+	return err
+}
+
+const setFeedParseWarnings = `-- name: SetFeedParseWarnings :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	parse_warnings = $3
+    WHERE id = $1
+`
+
+type SetFeedParseWarningsParams struct {
+	ID            uuid.UUID
+	UpdatedAt     time.Time
+	ParseWarnings []string
+}
+
+func (q *Queries) SetFeedParseWarnings(ctx context.Context, arg SetFeedParseWarningsParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedParseWarnings, arg.ID, arg.UpdatedAt, pq.Array(arg.ParseWarnings))
+	return err
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	keep_posts = $3,
+	keep_for_seconds = $4
+    WHERE id = $1
+`
+
+type SetFeedRetentionParams struct {
+	ID             uuid.UUID
+	UpdatedAt      time.Time
+	KeepPosts      sql.NullInt32
+	KeepForSeconds sql.NullInt64
+}
+
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRetention,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.KeepPosts,
+		arg.KeepForSeconds,
+	)
+	return err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	url = $3
+    WHERE id = $1
+`
+
+type UpdateFeedURLParams struct {
+	ID        uuid.UUID
+	UpdatedAt time.Time
+	Url       string
+}
+
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedURL, arg.ID, arg.UpdatedAt, arg.Url)
+	return err
+}
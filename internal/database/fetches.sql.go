@@ -0,0 +1,123 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: fetches.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFetch = `-- name: CreateFetch :exec
+INSERT INTO fetches (id, created_at, feed_id, status_code, bytes_transferred, bytes_decoded, duration_ms, error)
+	VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7,
+		$8
+	)
+`
+
+type CreateFetchParams struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	FeedID           uuid.UUID
+	StatusCode       int32
+	BytesTransferred int64
+	BytesDecoded     int64
+	DurationMs       int64
+	Error            string
+}
+
+func (q *Queries) CreateFetch(ctx context.Context, arg CreateFetchParams) error {
+	_, err := q.db.ExecContext(ctx, createFetch,
+		arg.ID,
+		arg.CreatedAt,
+		arg.FeedID,
+		arg.StatusCode,
+		arg.BytesTransferred,
+		arg.BytesDecoded,
+		arg.DurationMs,
+		arg.Error,
+	)
+	return err
+}
+
+const deleteFetches = `-- name: DeleteFetches :exec
+DELETE FROM fetches
+`
+
+func (q *Queries) DeleteFetches(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFetches)
+	return err
+}
+
+const getFetchStats = `-- name: GetFetchStats :many
+SELECT
+	feeds.name,
+	feeds.url,
+	COUNT(*) AS fetches,
+	COUNT(*) FILTER (WHERE fetches.status_code <> 200)::BIGINT AS failures,
+	SUM(fetches.bytes_transferred)::BIGINT AS bytes_transferred,
+	SUM(fetches.bytes_decoded)::BIGINT AS bytes_decoded,
+	AVG(fetches.duration_ms)::BIGINT AS avg_duration_ms,
+	MAX(fetches.duration_ms)::BIGINT AS max_duration_ms,
+	(ARRAY_AGG(fetches.status_code ORDER BY fetches.created_at DESC))[1]::INTEGER AS last_status
+FROM fetches
+INNER JOIN feeds ON fetches.feed_id = feeds.id
+WHERE fetches.created_at >= $1
+GROUP BY feeds.id
+`
+
+type GetFetchStatsRow struct {
+	Name             string
+	Url              string
+	Fetches          int64
+	Failures         int64
+	BytesTransferred int64
+	BytesDecoded     int64
+	AvgDurationMs    int64
+	MaxDurationMs    int64
+	LastStatus       int32
+}
+
+func (q *Queries) GetFetchStats(ctx context.Context, createdAt time.Time) ([]GetFetchStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFetchStats, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFetchStatsRow
+	for rows.Next() {
+		var i GetFetchStatsRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Url,
+			&i.Fetches,
+			&i.Failures,
+			&i.BytesTransferred,
+			&i.BytesDecoded,
+			&i.AvgDurationMs,
+			&i.MaxDurationMs,
+			&i.LastStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
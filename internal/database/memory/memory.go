@@ -0,0 +1,802 @@
+// Package memory is an in-memory database.Store for tests. It keeps the
+// constraints the handlers rely on: unique names and urls, foreign keys, and
+// cascading deletes.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+type Store struct {
+	txMu       sync.Mutex
+	mu         sync.Mutex
+	users      []database.User
+	feeds      []database.Feed
+	follows    []database.FeedFollow
+	posts      []database.Post
+	postStates []database.PostState
+	enclosures []database.Enclosure
+	fetches    []database.Fetch
+}
+
+func New() *Store {
+	return &Store{}
+}
+
+var _ database.Store = (*Store)(nil)
+
+// InTx runs fn against the store and puts every table back the way it was if
+// fn fails. Transactions run one at a time, but they aren't isolated from
+// queries made outside of one.
+func (s *Store) InTx(ctx context.Context, fn func(database.Querier) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+	s.mu.Lock()
+	saved := Store{
+		users:      slices.Clone(s.users),
+		feeds:      slices.Clone(s.feeds),
+		follows:    slices.Clone(s.follows),
+		posts:      slices.Clone(s.posts),
+		postStates: slices.Clone(s.postStates),
+		enclosures: slices.Clone(s.enclosures),
+		fetches:    slices.Clone(s.fetches),
+	}
+	s.mu.Unlock()
+	if err := fn(s); err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.users, s.feeds, s.follows, s.fetches = saved.users, saved.feeds, saved.follows, saved.fetches
+		s.posts, s.postStates, s.enclosures = saved.posts, saved.postStates, saved.enclosures
+		return err
+	}
+	return nil
+}
+
+func uniqueErr(constraint string) error {
+	return fmt.Errorf("duplicate key value violates unique constraint %q", constraint)
+}
+
+func foreignKeyErr(constraint string) error {
+	return fmt.Errorf("insert violates foreign key constraint %q", constraint)
+}
+
+func restrictErr(constraint string) error {
+	return fmt.Errorf("delete violates foreign key constraint %q", constraint)
+}
+
+func find[T any](items []T, match func(T) bool) (T, error) {
+	if i := slices.IndexFunc(items, match); i >= 0 {
+		return items[i], nil
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
+
+func filter[T any](items []T, match func(T) bool) []T {
+	var matched []T
+	for _, item := range items {
+		if match(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+func clone(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return slices.Clone(s)
+}
+
+func (s *Store) AddFeed(ctx context.Context, arg database.AddFeedParams) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.feeds, func(f database.Feed) bool { return f.Url == arg.Url }) {
+		return database.Feed{}, uniqueErr("feeds_url_key")
+	}
+	if !slices.ContainsFunc(s.users, func(u database.User) bool { return u.ID == arg.UserID }) {
+		return database.Feed{}, foreignKeyErr("feeds_user_id_fkey")
+	}
+	feed := database.Feed{
+		ID:            arg.ID,
+		CreatedAt:     arg.CreatedAt,
+		UpdatedAt:     arg.UpdatedAt,
+		Name:          arg.Name,
+		Url:           arg.Url,
+		UserID:        arg.UserID,
+		ParseWarnings: []string{},
+		Credentials:   arg.Credentials,
+	}
+	s.feeds = append(s.feeds, feed)
+	return feed, nil
+}
+
+func (s *Store) CreateEnclosure(ctx context.Context, arg database.CreateEnclosureParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.enclosures, func(e database.Enclosure) bool {
+		return e.PostID == arg.PostID && e.Url == arg.Url
+	}) {
+		return nil
+	}
+	if !slices.ContainsFunc(s.posts, func(p database.Post) bool { return p.ID == arg.PostID }) {
+		return foreignKeyErr("enclosures_post_id_fkey")
+	}
+	s.enclosures = append(s.enclosures, database.Enclosure(arg))
+	return nil
+}
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == arg.FeedID
+	}) {
+		return database.CreateFeedFollowRow{}, uniqueErr("feed_follows_user_id_feed_id_key")
+	}
+	user, err := find(s.users, func(u database.User) bool { return u.ID == arg.UserID })
+	if err != nil {
+		return database.CreateFeedFollowRow{}, foreignKeyErr("feed_follows_user_id_fkey")
+	}
+	feed, err := find(s.feeds, func(f database.Feed) bool { return f.ID == arg.FeedID })
+	if err != nil {
+		return database.CreateFeedFollowRow{}, foreignKeyErr("feed_follows_feed_id_fkey")
+	}
+	s.follows = append(s.follows, database.FeedFollow(arg))
+	return database.CreateFeedFollowRow{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+		UserName:  user.Name,
+		FeedName:  feed.Name,
+	}, nil
+}
+
+func (s *Store) CreateFetch(ctx context.Context, arg database.CreateFetchParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches = append(s.fetches, database.Fetch(arg))
+	return nil
+}
+
+func (s *Store) CreatePost(ctx context.Context, arg database.CreatePostParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.posts, func(p database.Post) bool { return p.Url == arg.Url }) {
+		return 0, nil
+	}
+	if !slices.ContainsFunc(s.feeds, func(f database.Feed) bool { return f.ID == arg.FeedID }) {
+		return 0, foreignKeyErr("posts_feed_id_fkey")
+	}
+	post := database.Post(arg)
+	post.Authors = clone(arg.Authors)
+	post.Categories = clone(arg.Categories)
+	s.posts = append(s.posts, post)
+	return 1, nil
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.users, func(u database.User) bool { return u.Name == arg.Name }) {
+		return database.User{}, uniqueErr("users_name_key")
+	}
+	user := database.User{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+	}
+	s.users = append(s.users, user)
+	return user, nil
+}
+
+func (s *Store) DeleteAllFeeds(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteFeeds(func(database.Feed) bool { return true })
+	return nil
+}
+
+func (s *Store) DeleteAllPosts(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletePosts(func(database.Post) bool { return true })
+	return nil
+}
+
+func (s *Store) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteFeeds(func(f database.Feed) bool { return f.ID == id })
+	return nil
+}
+
+func (s *Store) DeleteFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.follows = slices.DeleteFunc(s.follows, func(f database.FeedFollow) bool { return f.UserID == userID })
+	return nil
+}
+
+func (s *Store) DeleteFetches(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches = nil
+	return nil
+}
+
+func (s *Store) DeletePost(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletePosts(func(p database.Post) bool { return p.ID == id })
+	return nil
+}
+
+func (s *Store) DeletePostStatesForUser(ctx context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.postStates = slices.DeleteFunc(s.postStates, func(ps database.PostState) bool { return ps.UserID == userID })
+	return nil
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Feeds don't cascade from their owner.
+	if slices.ContainsFunc(s.feeds, func(f database.Feed) bool { return f.UserID == id }) {
+		return restrictErr("feeds_user_id_fkey")
+	}
+	s.users = slices.DeleteFunc(s.users, func(u database.User) bool { return u.ID == id })
+	s.follows = slices.DeleteFunc(s.follows, func(f database.FeedFollow) bool { return f.UserID == id })
+	s.postStates = slices.DeleteFunc(s.postStates, func(ps database.PostState) bool { return ps.UserID == id })
+	return nil
+}
+
+// deleteFeeds removes the matching feeds and everything that cascades from
+// them.
+func (s *Store) deleteFeeds(match func(database.Feed) bool) {
+	gone := map[uuid.UUID]bool{}
+	s.feeds = slices.DeleteFunc(s.feeds, func(f database.Feed) bool {
+		gone[f.ID] = match(f)
+		return gone[f.ID]
+	})
+	s.follows = slices.DeleteFunc(s.follows, func(f database.FeedFollow) bool { return gone[f.FeedID] })
+	s.fetches = slices.DeleteFunc(s.fetches, func(f database.Fetch) bool { return gone[f.FeedID] })
+	s.deletePosts(func(p database.Post) bool { return gone[p.FeedID] })
+}
+
+func (s *Store) deletePosts(match func(database.Post) bool) {
+	gone := map[uuid.UUID]bool{}
+	s.posts = slices.DeleteFunc(s.posts, func(p database.Post) bool {
+		gone[p.ID] = match(p)
+		return gone[p.ID]
+	})
+	s.postStates = slices.DeleteFunc(s.postStates, func(ps database.PostState) bool { return gone[ps.PostID] })
+	s.enclosures = slices.DeleteFunc(s.enclosures, func(e database.Enclosure) bool { return gone[e.PostID] })
+}
+
+func (s *Store) Feeds(ctx context.Context) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.feeds), nil
+}
+
+func (s *Store) GetEnclosuresForFeed(ctx context.Context, feedID uuid.UUID) ([]database.GetEnclosuresForFeedRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetEnclosuresForFeedRow
+	for _, enclosure := range s.enclosures {
+		post, err := find(s.posts, func(p database.Post) bool { return p.ID == enclosure.PostID })
+		if err != nil || post.FeedID != feedID {
+			continue
+		}
+		rows = append(rows, database.GetEnclosuresForFeedRow{
+			ID:          enclosure.ID,
+			CreatedAt:   enclosure.CreatedAt,
+			UpdatedAt:   enclosure.UpdatedAt,
+			PostID:      enclosure.PostID,
+			Url:         enclosure.Url,
+			Length:      enclosure.Length,
+			MimeType:    enclosure.MimeType,
+			Duration:    enclosure.Duration,
+			Episode:     enclosure.Episode,
+			Season:      enclosure.Season,
+			ImageUrl:    enclosure.ImageUrl,
+			PostTitle:   post.Title,
+			PublishedAt: post.PublishedAt,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b database.GetEnclosuresForFeedRow) int {
+		return b.PublishedAt.Compare(a.PublishedAt)
+	})
+	return rows, nil
+}
+
+func (s *Store) GetEnclosuresForPost(ctx context.Context, postID uuid.UUID) ([]database.Enclosure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.enclosures, func(e database.Enclosure) bool { return e.PostID == postID }), nil
+}
+
+func (s *Store) GetFeedByID(ctx context.Context, id uuid.UUID) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return find(s.feeds, func(f database.Feed) bool { return f.ID == id })
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, url string) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return find(s.feeds, func(f database.Feed) bool { return f.Url == url })
+}
+
+func (s *Store) GetFeedFollowsForFeed(ctx context.Context, feedID uuid.UUID) ([]database.FeedFollow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	follows := filter(s.follows, func(f database.FeedFollow) bool { return f.FeedID == feedID })
+	slices.SortStableFunc(follows, func(a, b database.FeedFollow) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return follows, nil
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.FeedFollow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.follows, func(f database.FeedFollow) bool { return f.UserID == userID }), nil
+}
+
+func (s *Store) GetFeedsWithUnreadCount(ctx context.Context, userID uuid.UUID) ([]database.GetFeedsWithUnreadCountRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetFeedsWithUnreadCountRow
+	for _, follow := range s.follows {
+		if follow.UserID != userID {
+			continue
+		}
+		feed, err := find(s.feeds, func(f database.Feed) bool { return f.ID == follow.FeedID })
+		if err != nil {
+			continue
+		}
+		row := database.GetFeedsWithUnreadCountRow{ID: feed.ID, Name: feed.Name, Url: feed.Url}
+		for _, post := range s.posts {
+			if post.FeedID == feed.ID && !s.postState(userID, post.ID).Read {
+				row.Unread++
+			}
+		}
+		rows = append(rows, row)
+	}
+	slices.SortStableFunc(rows, func(a, b database.GetFeedsWithUnreadCountRow) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return rows, nil
+}
+
+func (s *Store) postState(userID, postID uuid.UUID) database.PostState {
+	state, _ := find(s.postStates, func(ps database.PostState) bool {
+		return ps.UserID == userID && ps.PostID == postID
+	})
+	return state
+}
+
+func (s *Store) GetFetchStats(ctx context.Context, createdAt time.Time) ([]database.GetFetchStatsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetFetchStatsRow
+	for _, feed := range s.feeds {
+		row := database.GetFetchStatsRow{Name: feed.Name, Url: feed.Url}
+		var total int64
+		var latest time.Time
+		for _, fetch := range s.fetches {
+			if fetch.FeedID != feed.ID || fetch.CreatedAt.Before(createdAt) {
+				continue
+			}
+			row.Fetches++
+			if fetch.StatusCode != 200 {
+				row.Failures++
+			}
+			row.BytesTransferred += fetch.BytesTransferred
+			row.BytesDecoded += fetch.BytesDecoded
+			total += fetch.DurationMs
+			row.MaxDurationMs = max(row.MaxDurationMs, fetch.DurationMs)
+			if !fetch.CreatedAt.Before(latest) {
+				latest = fetch.CreatedAt
+				row.LastStatus = fetch.StatusCode
+			}
+		}
+		if row.Fetches == 0 {
+			continue
+		}
+		row.AvgDurationMs = total / row.Fetches
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *Store) GetNextFeedsToFetch(ctx context.Context, arg database.GetNextFeedsToFetchParams) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	feeds := filter(s.feeds, func(f database.Feed) bool {
+		return !f.RetiredAt.Valid && (!f.NextFetchAt.Valid || !f.NextFetchAt.Time.After(arg.Now))
+	})
+	slices.SortStableFunc(feeds, func(a, b database.Feed) int {
+		switch {
+		case !a.LastFetchedAt.Valid && !b.LastFetchedAt.Valid:
+			return 0
+		case !a.LastFetchedAt.Valid:
+			return -1
+		case !b.LastFetchedAt.Valid:
+			return 1
+		}
+		return a.LastFetchedAt.Time.Compare(b.LastFetchedAt.Time)
+	})
+	return feeds[:min(len(feeds), int(arg.MaxFeeds))], nil
+}
+
+func (s *Store) GetPostByID(ctx context.Context, id uuid.UUID) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return find(s.posts, func(p database.Post) bool { return p.ID == id })
+}
+
+func (s *Store) GetPostByURL(ctx context.Context, url string) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return find(s.posts, func(p database.Post) bool { return p.Url == url })
+}
+
+func (s *Store) GetPostStatesForUser(ctx context.Context, userID uuid.UUID) ([]database.PostState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.postStates, func(ps database.PostState) bool { return ps.UserID == userID }), nil
+}
+
+func (s *Store) GetPostsByFeedID(ctx context.Context, feedID uuid.UUID) ([]database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	posts := filter(s.posts, func(p database.Post) bool { return p.FeedID == feedID })
+	slices.SortStableFunc(posts, func(a, b database.Post) int {
+		return a.PublishedAt.Compare(b.PublishedAt)
+	})
+	return posts, nil
+}
+
+func (s *Store) GetPostsByUser(ctx context.Context, arg database.GetPostsByUserParams) ([]database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, err := find(s.users, func(u database.User) bool { return u.Name == arg.Name })
+	if err != nil {
+		return nil, nil
+	}
+	posts := filter(s.posts, func(p database.Post) bool {
+		feed, err := find(s.feeds, func(f database.Feed) bool { return f.ID == p.FeedID })
+		return err == nil && feed.UserID == user.ID
+	})
+	slices.SortStableFunc(posts, func(a, b database.Post) int {
+		return a.PublishedAt.Compare(b.PublishedAt)
+	})
+	return posts[:min(len(posts), int(arg.Limit))], nil
+}
+
+func (s *Store) GetPostsForFeed(ctx context.Context, arg database.GetPostsForFeedParams) ([]database.GetPostsForFeedRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetPostsForFeedRow
+	for _, post := range s.posts {
+		if post.FeedID != arg.FeedID {
+			continue
+		}
+		state := s.postState(arg.UserID, post.ID)
+		rows = append(rows, database.GetPostsForFeedRow{
+			ID:          post.ID,
+			CreatedAt:   post.CreatedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID:      post.FeedID,
+			Content:     post.Content,
+			Authors:     post.Authors,
+			Categories:  post.Categories,
+			CommentsUrl: post.CommentsUrl,
+			ImageUrl:    post.ImageUrl,
+			Read:        state.Read,
+			Starred:     state.Starred,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b database.GetPostsForFeedRow) int {
+		return b.PublishedAt.Compare(a.PublishedAt)
+	})
+	return rows, nil
+}
+
+func (s *Store) GetPostsForRetention(ctx context.Context, feedID uuid.UUID) ([]database.GetPostsForRetentionRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rows []database.GetPostsForRetentionRow
+	for _, post := range s.posts {
+		if post.FeedID != feedID {
+			continue
+		}
+		row := database.GetPostsForRetentionRow{
+			ID:          post.ID,
+			Title:       post.Title,
+			Url:         post.Url,
+			PublishedAt: post.PublishedAt,
+			Starred: slices.ContainsFunc(s.postStates, func(ps database.PostState) bool {
+				return ps.PostID == post.ID && ps.Starred
+			}),
+			Unread: slices.ContainsFunc(s.follows, func(f database.FeedFollow) bool {
+				return f.FeedID == feedID && !s.postState(f.UserID, post.ID).Read
+			}),
+		}
+		rows = append(rows, row)
+	}
+	slices.SortStableFunc(rows, func(a, b database.GetPostsForRetentionRow) int {
+		return b.PublishedAt.Compare(a.PublishedAt)
+	})
+	return rows, nil
+}
+
+func (s *Store) GetUser(ctx context.Context, name string) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return find(s.users, func(u database.User) bool { return u.Name == name })
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return find(s.users, func(u database.User) bool { return u.ID == id })
+}
+
+func (s *Store) GetUsers(ctx context.Context) ([]database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.users), nil
+}
+
+// updateFeed applies update to the feed with id, if there is one.
+func (s *Store) updateFeed(id uuid.UUID, update func(*database.Feed)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := slices.IndexFunc(s.feeds, func(f database.Feed) bool { return f.ID == id }); i >= 0 {
+		update(&s.feeds[i])
+	}
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) error {
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.LastFetchedAt = arg.LastFetchedAt
+	})
+	return nil
+}
+
+func (s *Store) MergeFeedFollows(ctx context.Context, arg database.MergeFeedFollowsParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, follow := range filter(s.follows, func(f database.FeedFollow) bool { return f.FeedID == arg.FromFeedID }) {
+		if slices.ContainsFunc(s.follows, func(f database.FeedFollow) bool {
+			return f.UserID == follow.UserID && f.FeedID == arg.IntoFeedID
+		}) {
+			continue
+		}
+		s.follows = append(s.follows, database.FeedFollow{
+			ID:        uuid.New(),
+			CreatedAt: follow.CreatedAt,
+			UpdatedAt: arg.UpdatedAt,
+			UserID:    follow.UserID,
+			FeedID:    arg.IntoFeedID,
+		})
+	}
+	return nil
+}
+
+func (s *Store) MergeFeedPosts(ctx context.Context, arg database.MergeFeedPostsParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.posts {
+		if s.posts[i].FeedID == arg.FromFeedID {
+			s.posts[i].FeedID = arg.IntoFeedID
+		}
+	}
+	return nil
+}
+
+func (s *Store) RenameUser(ctx context.Context, arg database.RenameUserParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) error {
+		if slices.ContainsFunc(s.users, func(other database.User) bool { return other.Name == arg.Name && other.ID != arg.ID }) {
+			return uniqueErr("users_name_key")
+		}
+		u.UpdatedAt = arg.UpdatedAt
+		u.Name = arg.Name
+		return nil
+	})
+}
+
+func (s *Store) Reset(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.feeds) > 0 {
+		return restrictErr("feeds_user_id_fkey")
+	}
+	// Without feeds, all that is left is users.
+	s.users = nil
+	return nil
+}
+
+func (s *Store) ResetFeedFetchState(ctx context.Context, updatedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.feeds {
+		s.feeds[i].UpdatedAt = updatedAt
+		s.feeds[i].LastFetchedAt = sql.NullTime{}
+		s.feeds[i].NextFetchAt = sql.NullTime{}
+		s.feeds[i].ParseWarnings = []string{}
+	}
+	return nil
+}
+
+func (s *Store) RetireFeed(ctx context.Context, arg database.RetireFeedParams) error {
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.RetiredAt = arg.RetiredAt
+	})
+	return nil
+}
+
+func (s *Store) SetFeedFetchFullContent(ctx context.Context, arg database.SetFeedFetchFullContentParams) error {
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.FetchFullContent = arg.FetchFullContent
+	})
+	return nil
+}
+
+func (s *Store) SetFeedNextFetch(ctx context.Context, arg database.SetFeedNextFetchParams) error {
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.NextFetchAt = arg.NextFetchAt
+	})
+	return nil
+}
+
+func (s *Store) SetFeedOwner(ctx context.Context, arg database.SetFeedOwnerParams) error {
+	s.mu.Lock()
+	if !slices.ContainsFunc(s.users, func(u database.User) bool { return u.ID == arg.UserID }) {
+		s.mu.Unlock()
+		return foreignKeyErr("feeds_user_id_fkey")
+	}
+	s.mu.Unlock()
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.UserID = arg.UserID
+	})
+	return nil
+}
+
+func (s *Store) SetFeedParseWarnings(ctx context.Context, arg database.SetFeedParseWarningsParams) error {
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.ParseWarnings = clone(arg.ParseWarnings)
+	})
+	return nil
+}
+
+func (s *Store) SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) error {
+	s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.UpdatedAt = arg.UpdatedAt
+		f.KeepPosts = arg.KeepPosts
+		f.KeepForSeconds = arg.KeepForSeconds
+	})
+	return nil
+}
+
+// setPostState inserts or updates the state of a post for a user, like the
+// ON CONFLICT upserts in SQL.
+func (s *Store) setPostState(state database.PostState, update func(*database.PostState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.ContainsFunc(s.posts, func(p database.Post) bool { return p.ID == state.PostID }) {
+		return foreignKeyErr("post_states_post_id_fkey")
+	}
+	i := slices.IndexFunc(s.postStates, func(ps database.PostState) bool {
+		return ps.UserID == state.UserID && ps.PostID == state.PostID
+	})
+	if i < 0 {
+		s.postStates = append(s.postStates, state)
+		i = len(s.postStates) - 1
+	}
+	s.postStates[i].UpdatedAt = state.UpdatedAt
+	update(&s.postStates[i])
+	return nil
+}
+
+func (s *Store) SetPostRead(ctx context.Context, arg database.SetPostReadParams) error {
+	return s.setPostState(database.PostState{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		PostID:    arg.PostID,
+	}, func(ps *database.PostState) {
+		ps.Read = arg.Read
+	})
+}
+
+func (s *Store) SetPostStarred(ctx context.Context, arg database.SetPostStarredParams) error {
+	return s.setPostState(database.PostState{
+		ID:        arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		PostID:    arg.PostID,
+	}, func(ps *database.PostState) {
+		ps.Starred = arg.Starred
+	})
+}
+
+func (s *Store) UnfollowFeed(ctx context.Context, arg database.UnfollowFeedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.follows = slices.DeleteFunc(s.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == arg.FeedID
+	})
+	return nil
+}
+
+func (s *Store) UpdateFeedURL(ctx context.Context, arg database.UpdateFeedURLParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.feeds, func(f database.Feed) bool { return f.Url == arg.Url && f.ID != arg.ID }) {
+		return uniqueErr("feeds_url_key")
+	}
+	if i := slices.IndexFunc(s.feeds, func(f database.Feed) bool { return f.ID == arg.ID }); i >= 0 {
+		s.feeds[i].UpdatedAt = arg.UpdatedAt
+		s.feeds[i].Url = arg.Url
+	}
+	return nil
+}
+
+func (s *Store) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	return s.updateUser(arg.ID, func(u *database.User) error {
+		u.UpdatedAt = arg.UpdatedAt
+		u.DisplayName = arg.DisplayName
+		u.Email = arg.Email
+		u.Timezone = arg.Timezone
+		u.BrowseLimit = arg.BrowseLimit
+		return nil
+	})
+}
+
+// updateUser applies update to the user with id and returns the result, like
+// an UPDATE ... RETURNING.
+func (s *Store) updateUser(id uuid.UUID, update func(*database.User) error) (database.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.users, func(u database.User) bool { return u.ID == id })
+	if i < 0 {
+		return database.User{}, sql.ErrNoRows
+	}
+	user := s.users[i]
+	if err := update(&user); err != nil {
+		return database.User{}, err
+	}
+	s.users[i] = user
+	return user, nil
+}
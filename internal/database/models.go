@@ -11,14 +11,35 @@ import (
 	"github.com/google/uuid"
 )
 
+type Enclosure struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	PostID    uuid.UUID
+	Url       string
+	Length    int64
+	MimeType  string
+	Duration  string
+	Episode   sql.NullInt32
+	Season    sql.NullInt32
+	ImageUrl  string
+}
+
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	LastFetchedAt    sql.NullTime
+	FetchFullContent bool
+	ParseWarnings    []string
+	RetiredAt        sql.NullTime
+	NextFetchAt      sql.NullTime
+	Credentials      []byte
+	KeepPosts        sql.NullInt32
+	KeepForSeconds   sql.NullInt64
 }
 
 type FeedFollow struct {
@@ -29,6 +50,17 @@ type FeedFollow struct {
 	FeedID    uuid.UUID
 }
 
+type Fetch struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	FeedID           uuid.UUID
+	StatusCode       int32
+	BytesTransferred int64
+	BytesDecoded     int64
+	DurationMs       int64
+	Error            string
+}
+
 type Post struct {
 	ID          uuid.UUID
 	CreatedAt   time.Time
@@ -38,11 +70,30 @@ type Post struct {
 	Description string
 	PublishedAt time.Time
 	FeedID      uuid.UUID
+	Content     sql.NullString
+	Authors     []string
+	Categories  []string
+	CommentsUrl string
+	ImageUrl    string
 }
 
-type User struct {
+type PostState struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Name      string
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Read      bool
+	Starred   bool
+}
+
+type User struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	DisplayName string
+	Email       string
+	Timezone    string
+	BrowseLimit int32
 }
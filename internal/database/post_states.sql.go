@@ -0,0 +1,254 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: post_states.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const deletePostStatesForUser = `-- name: DeletePostStatesForUser :exec
+DELETE FROM post_states WHERE user_id = $1
+`
+
+func (q *Queries) DeletePostStatesForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deletePostStatesForUser, userID)
+	return err
+}
+
+const getFeedsWithUnreadCount = `-- name: GetFeedsWithUnreadCount :many
+SELECT
+    feeds.id,
+    feeds.name,
+    feeds.url,
+    COUNT(posts.id) FILTER (WHERE COALESCE(post_states.read, FALSE) = FALSE) AS unread
+FROM feed_follows
+    INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+    LEFT JOIN posts ON posts.feed_id = feeds.id
+    LEFT JOIN post_states ON post_states.post_id = posts.id
+	AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+GROUP BY feeds.id
+ORDER BY feeds.name
+`
+
+type GetFeedsWithUnreadCountRow struct {
+	ID     uuid.UUID
+	Name   string
+	Url    string
+	Unread int64
+}
+
+func (q *Queries) GetFeedsWithUnreadCount(ctx context.Context, userID uuid.UUID) ([]GetFeedsWithUnreadCountRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsWithUnreadCount, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedsWithUnreadCountRow
+	for rows.Next() {
+		var i GetFeedsWithUnreadCountRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.Unread,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostStatesForUser = `-- name: GetPostStatesForUser :many
+SELECT id, created_at, updated_at, user_id, post_id, read, starred FROM post_states WHERE user_id = $1
+`
+
+func (q *Queries) GetPostStatesForUser(ctx context.Context, userID uuid.UUID) ([]PostState, error) {
+	rows, err := q.db.QueryContext(ctx, getPostStatesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PostState
+	for rows.Next() {
+		var i PostState
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.PostID,
+			&i.Read,
+			&i.Starred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForFeed = `-- name: GetPostsForFeed :many
+SELECT
+    posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.authors, posts.categories, posts.comments_url, posts.image_url,
+    COALESCE(post_states.read, FALSE)::BOOLEAN AS read,
+    COALESCE(post_states.starred, FALSE)::BOOLEAN AS starred
+FROM posts
+    LEFT JOIN post_states ON post_states.post_id = posts.id
+	AND post_states.user_id = $1
+WHERE posts.feed_id = $2
+ORDER BY posts.published_at DESC
+`
+
+type GetPostsForFeedParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+type GetPostsForFeedRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description string
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+	Content     sql.NullString
+	Authors     []string
+	Categories  []string
+	CommentsUrl string
+	ImageUrl    string
+	Read        bool
+	Starred     bool
+}
+
+func (q *Queries) GetPostsForFeed(ctx context.Context, arg GetPostsForFeedParams) ([]GetPostsForFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForFeed, arg.UserID, arg.FeedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForFeedRow
+	for rows.Next() {
+		var i GetPostsForFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			pq.Array(&i.Authors),
+			pq.Array(&i.Categories),
+			&i.CommentsUrl,
+			&i.ImageUrl,
+			&i.Read,
+			&i.Starred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPostRead = `-- name: SetPostRead :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, read)
+    VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+    )
+    ON CONFLICT (user_id, post_id) DO UPDATE
+    SET updated_at = EXCLUDED.updated_at, read = EXCLUDED.read
+`
+
+type SetPostReadParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Read      bool
+}
+
+func (q *Queries) SetPostRead(ctx context.Context, arg SetPostReadParams) error {
+	_, err := q.db.ExecContext(ctx, setPostRead,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.PostID,
+		arg.Read,
+	)
+	return err
+}
+
+const setPostStarred = `-- name: SetPostStarred :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, starred)
+    VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+    )
+    ON CONFLICT (user_id, post_id) DO UPDATE
+    SET updated_at = EXCLUDED.updated_at, starred = EXCLUDED.starred
+`
+
+type SetPostStarredParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Starred   bool
+}
+
+func (q *Queries) SetPostStarred(ctx context.Context, arg SetPostStarredParams) error {
+	_, err := q.db.ExecContext(ctx, setPostStarred,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.PostID,
+		arg.Starred,
+	)
+	return err
+}
@@ -7,13 +7,15 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-const createPost = `-- name: CreatePost :exec
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+const createPost = `-- name: CreatePost :execrows
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, content, authors, categories, comments_url, image_url)
 	VALUES (
 		$1,
 		$2,
@@ -22,9 +24,14 @@ INSERT INTO posts (id, created_at, updated_at, title, url, description, publishe
 		$5,
 		$6,
 		$7,
-		$8
+		$8,
+		$9,
+		$10,
+		$11,
+		$12,
+		$13
 	)
-	RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+	ON CONFLICT (url) DO NOTHING
 `
 
 type CreatePostParams struct {
@@ -36,10 +43,15 @@ type CreatePostParams struct {
 	Description string
 	PublishedAt time.Time
 	FeedID      uuid.UUID
+	Content     sql.NullString
+	Authors     []string
+	Categories  []string
+	CommentsUrl string
+	ImageUrl    string
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
-	_, err := q.db.ExecContext(ctx, createPost,
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
 		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -48,12 +60,129 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Content,
+		pq.Array(arg.Authors),
+		pq.Array(arg.Categories),
+		arg.CommentsUrl,
+		arg.ImageUrl,
 	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteAllPosts = `-- name: DeleteAllPosts :exec
+DELETE FROM posts
+`
+
+func (q *Queries) DeleteAllPosts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteAllPosts)
+	return err
+}
+
+const deletePost = `-- name: DeletePost :exec
+DELETE FROM posts WHERE id = $1
+`
+
+func (q *Queries) DeletePost(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deletePost, id)
 	return err
 }
 
+const getPostByID = `-- name: GetPostByID :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, authors, categories, comments_url, image_url FROM posts WHERE id = $1
+`
+
+func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		pq.Array(&i.Authors),
+		pq.Array(&i.Categories),
+		&i.CommentsUrl,
+		&i.ImageUrl,
+	)
+	return i, err
+}
+
+const getPostByURL = `-- name: GetPostByURL :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, authors, categories, comments_url, image_url FROM posts WHERE url = $1
+`
+
+func (q *Queries) GetPostByURL(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByURL, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Content,
+		pq.Array(&i.Authors),
+		pq.Array(&i.Categories),
+		&i.CommentsUrl,
+		&i.ImageUrl,
+	)
+	return i, err
+}
+
+const getPostsByFeedID = `-- name: GetPostsByFeedID :many
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, content, authors, categories, comments_url, image_url FROM posts WHERE feed_id = $1 ORDER BY published_at
+`
+
+func (q *Queries) GetPostsByFeedID(ctx context.Context, feedID uuid.UUID) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsByFeedID, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Content,
+			pq.Array(&i.Authors),
+			pq.Array(&i.Categories),
+			&i.CommentsUrl,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPostsByUser = `-- name: GetPostsByUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id FROM posts
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.authors, posts.categories, posts.comments_url, posts.image_url FROM posts
 	JOIN feeds ON posts.feed_id = feeds.id
 	JOIN users ON feeds.user_id = users.id
 	WHERE users.name = $1
@@ -84,6 +213,71 @@ func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams)
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			pq.Array(&i.Authors),
+			pq.Array(&i.Categories),
+			&i.CommentsUrl,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForRetention = `-- name: GetPostsForRetention :many
+SELECT posts.id, posts.title, posts.url, posts.published_at,
+	EXISTS (
+		SELECT 1 FROM post_states
+		WHERE post_states.post_id = posts.id AND post_states.starred
+	) AS starred,
+	EXISTS (
+		SELECT 1 FROM feed_follows
+		WHERE feed_follows.feed_id = posts.feed_id
+		AND NOT EXISTS (
+			SELECT 1 FROM post_states
+			WHERE post_states.post_id = posts.id
+			AND post_states.user_id = feed_follows.user_id
+			AND post_states.read
+		)
+	) AS unread
+	FROM posts
+	WHERE posts.feed_id = $1
+	ORDER BY posts.published_at DESC
+`
+
+type GetPostsForRetentionRow struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	PublishedAt time.Time
+	Starred     bool
+	Unread      bool
+}
+
+func (q *Queries) GetPostsForRetention(ctx context.Context, feedID uuid.UUID) ([]GetPostsForRetentionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForRetention, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForRetentionRow
+	for rows.Next() {
+		var i GetPostsForRetentionRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.Starred,
+			&i.Unread,
 		); err != nil {
 			return nil, err
 		}
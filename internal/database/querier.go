@@ -0,0 +1,68 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Querier interface {
+	AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error)
+	CreateEnclosure(ctx context.Context, arg CreateEnclosureParams) error
+	CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error)
+	CreateFetch(ctx context.Context, arg CreateFetchParams) error
+	CreatePost(ctx context.Context, arg CreatePostParams) (int64, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteAllFeeds(ctx context.Context) error
+	DeleteAllPosts(ctx context.Context) error
+	DeleteFeed(ctx context.Context, id uuid.UUID) error
+	DeleteFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error
+	DeleteFetches(ctx context.Context) error
+	DeletePost(ctx context.Context, id uuid.UUID) error
+	DeletePostStatesForUser(ctx context.Context, userID uuid.UUID) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	Feeds(ctx context.Context) ([]Feed, error)
+	GetEnclosuresForFeed(ctx context.Context, feedID uuid.UUID) ([]GetEnclosuresForFeedRow, error)
+	GetEnclosuresForPost(ctx context.Context, postID uuid.UUID) ([]Enclosure, error)
+	GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error)
+	GetFeedByURL(ctx context.Context, url string) (Feed, error)
+	GetFeedFollowsForFeed(ctx context.Context, feedID uuid.UUID) ([]FeedFollow, error)
+	GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]FeedFollow, error)
+	GetFeedsWithUnreadCount(ctx context.Context, userID uuid.UUID) ([]GetFeedsWithUnreadCountRow, error)
+	GetFetchStats(ctx context.Context, createdAt time.Time) ([]GetFetchStatsRow, error)
+	GetNextFeedsToFetch(ctx context.Context, arg GetNextFeedsToFetchParams) ([]Feed, error)
+	GetPostByID(ctx context.Context, id uuid.UUID) (Post, error)
+	GetPostByURL(ctx context.Context, url string) (Post, error)
+	GetPostStatesForUser(ctx context.Context, userID uuid.UUID) ([]PostState, error)
+	GetPostsByFeedID(ctx context.Context, feedID uuid.UUID) ([]Post, error)
+	GetPostsByUser(ctx context.Context, arg GetPostsByUserParams) ([]Post, error)
+	GetPostsForFeed(ctx context.Context, arg GetPostsForFeedParams) ([]GetPostsForFeedRow, error)
+	GetPostsForRetention(ctx context.Context, feedID uuid.UUID) ([]GetPostsForRetentionRow, error)
+	GetUser(ctx context.Context, name string) (User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error
+	MergeFeedFollows(ctx context.Context, arg MergeFeedFollowsParams) error
+	MergeFeedPosts(ctx context.Context, arg MergeFeedPostsParams) error
+	RenameUser(ctx context.Context, arg RenameUserParams) (User, error)
+	Reset(ctx context.Context) error
+	ResetFeedFetchState(ctx context.Context, updatedAt time.Time) error
+	RetireFeed(ctx context.Context, arg RetireFeedParams) error
+	SetFeedFetchFullContent(ctx context.Context, arg SetFeedFetchFullContentParams) error
+	SetFeedNextFetch(ctx context.Context, arg SetFeedNextFetchParams) error
+	SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) error
+	SetFeedParseWarnings(ctx context.Context, arg SetFeedParseWarningsParams) error
+	SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) error
+	SetPostRead(ctx context.Context, arg SetPostReadParams) error
+	SetPostStarred(ctx context.Context, arg SetPostStarredParams) error
+	UnfollowFeed(ctx context.Context, arg UnfollowFeedParams) error
+	UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+}
+
+var _ Querier = (*Queries)(nil)
@@ -0,0 +1,150 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: enclosures.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createEnclosure = `-- name: CreateEnclosure :exec
+INSERT INTO enclosures (id, created_at, updated_at, post_id, url, length, mime_type, duration, episode, season, image_url)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (post_id, url) DO NOTHING
+`
+
+type CreateEnclosureParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	PostID    uuid.UUID
+	Url       string
+	Length    int64
+	MimeType  string
+	Duration  string
+	Episode   sql.NullInt32
+	Season    sql.NullInt32
+	ImageUrl  string
+}
+
+func (q *Queries) CreateEnclosure(ctx context.Context, arg CreateEnclosureParams) error {
+	_, err := q.db.ExecContext(ctx, createEnclosure,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.PostID,
+		arg.Url,
+		arg.Length,
+		arg.MimeType,
+		arg.Duration,
+		arg.Episode,
+		arg.Season,
+		arg.ImageUrl,
+	)
+	return err
+}
+
+const getEnclosuresForFeed = `-- name: GetEnclosuresForFeed :many
+SELECT enclosures.id, enclosures.created_at, enclosures.updated_at, enclosures.post_id, enclosures.url, enclosures.length, enclosures.mime_type, enclosures.duration, enclosures.episode, enclosures.season, enclosures.image_url, posts.title AS post_title, posts.published_at FROM enclosures
+	JOIN posts ON enclosures.post_id = posts.id
+	WHERE posts.feed_id = ?
+	ORDER BY posts.published_at DESC
+`
+
+type GetEnclosuresForFeedRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	PostID      uuid.UUID
+	Url         string
+	Length      int64
+	MimeType    string
+	Duration    string
+	Episode     sql.NullInt32
+	Season      sql.NullInt32
+	ImageUrl    string
+	PostTitle   string
+	PublishedAt time.Time
+}
+
+func (q *Queries) GetEnclosuresForFeed(ctx context.Context, feedID uuid.UUID) ([]GetEnclosuresForFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getEnclosuresForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetEnclosuresForFeedRow
+	for rows.Next() {
+		var i GetEnclosuresForFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PostID,
+			&i.Url,
+			&i.Length,
+			&i.MimeType,
+			&i.Duration,
+			&i.Episode,
+			&i.Season,
+			&i.ImageUrl,
+			&i.PostTitle,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEnclosuresForPost = `-- name: GetEnclosuresForPost :many
+SELECT id, created_at, updated_at, post_id, url, length, mime_type, duration, episode, season, image_url FROM enclosures WHERE post_id = ?
+`
+
+func (q *Queries) GetEnclosuresForPost(ctx context.Context, postID uuid.UUID) ([]Enclosure, error) {
+	rows, err := q.db.QueryContext(ctx, getEnclosuresForPost, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Enclosure
+	for rows.Next() {
+		var i Enclosure
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PostID,
+			&i.Url,
+			&i.Length,
+			&i.MimeType,
+			&i.Duration,
+			&i.Episode,
+			&i.Season,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
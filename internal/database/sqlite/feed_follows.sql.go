@@ -0,0 +1,174 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: feed_follows.sql
+
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFeedFollow = `-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+	VALUES (?, ?, ?, ?, ?)
+	RETURNING id, created_at, updated_at, user_id, feed_id
+`
+
+type CreateFeedFollowParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, createFeedFollow,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.FeedID,
+	)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+	)
+	return i, err
+}
+
+const deleteFeedFollowsForUser = `-- name: DeleteFeedFollowsForUser :exec
+DELETE FROM feed_follows WHERE user_id = ?
+`
+
+func (q *Queries) DeleteFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedFollowsForUser, userID)
+	return err
+}
+
+const getFeedFollowNames = `-- name: GetFeedFollowNames :one
+SELECT users.name AS user_name, feeds.name AS feed_name
+FROM feed_follows
+    INNER JOIN users ON feed_follows.user_id = users.id
+    INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.id = ?
+`
+
+type GetFeedFollowNamesRow struct {
+	UserName string
+	FeedName string
+}
+
+func (q *Queries) GetFeedFollowNames(ctx context.Context, id uuid.UUID) (GetFeedFollowNamesRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollowNames, id)
+	var i GetFeedFollowNamesRow
+	err := row.Scan(&i.UserName, &i.FeedName)
+	return i, err
+}
+
+const getFeedFollowsForFeed = `-- name: GetFeedFollowsForFeed :many
+SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows WHERE feed_id = ? ORDER BY created_at
+`
+
+func (q *Queries) GetFeedFollowsForFeed(ctx context.Context, feedID uuid.UUID) ([]FeedFollow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedFollow
+	for rows.Next() {
+		var i FeedFollow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
+SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows WHERE user_id = ?
+`
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]FeedFollow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedFollow
+	for rows.Next() {
+		var i FeedFollow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const mergeFeedFollows = `-- name: MergeFeedFollows :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+    SELECT lower(hex(randomblob(16))), feed_follows.created_at, ?1, feed_follows.user_id, ?2
+    FROM feed_follows
+    WHERE feed_follows.feed_id = ?3
+    ON CONFLICT (user_id, feed_id) DO NOTHING
+`
+
+type MergeFeedFollowsParams struct {
+	UpdatedAt  time.Time
+	IntoFeedID uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MergeFeedFollows(ctx context.Context, arg MergeFeedFollowsParams) error {
+	_, err := q.db.ExecContext(ctx, mergeFeedFollows, arg.UpdatedAt, arg.IntoFeedID, arg.FromFeedID)
+	return err
+}
+
+const unfollowFeed = `-- name: UnfollowFeed :exec
+DELETE FROM feed_follows WHERE user_id = ? AND feed_id = ?
+`
+
+type UnfollowFeedParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+func (q *Queries) UnfollowFeed(ctx context.Context, arg UnfollowFeedParams) error {
+	_, err := q.db.ExecContext(ctx, unfollowFeed, arg.UserID, arg.FeedID)
+	return err
+}
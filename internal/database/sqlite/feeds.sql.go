@@ -0,0 +1,411 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: feeds.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addFeed = `-- name: AddFeed :one
+INSERT INTO feeds(id, created_at, updated_at, name, url, user_id, credentials)
+  VALUES (?, ?, ?, ?, ?, ?, ?)
+  RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds
+`
+
+type AddFeedParams struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Url         string
+	UserID      uuid.UUID
+	Credentials []byte
+}
+
+func (q *Queries) AddFeed(ctx context.Context, arg AddFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, addFeed,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Url,
+		arg.UserID,
+		arg.Credentials,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.ParseWarnings,
+		&i.RetiredAt,
+		&i.NextFetchAt,
+		&i.Credentials,
+		&i.KeepPosts,
+		&i.KeepForSeconds,
+	)
+	return i, err
+}
+
+const deleteAllFeeds = `-- name: DeleteAllFeeds :exec
+DELETE FROM feeds
+`
+
+func (q *Queries) DeleteAllFeeds(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteAllFeeds)
+	return err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = ?
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const feeds = `-- name: Feeds :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds
+`
+
+func (q *Queries) Feeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, feeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.FetchFullContent,
+			&i.ParseWarnings,
+			&i.RetiredAt,
+			&i.NextFetchAt,
+			&i.Credentials,
+			&i.KeepPosts,
+			&i.KeepForSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedByID = `-- name: GetFeedByID :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds WHERE id = ?
+`
+
+func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByID, id)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.ParseWarnings,
+		&i.RetiredAt,
+		&i.NextFetchAt,
+		&i.Credentials,
+		&i.KeepPosts,
+		&i.KeepForSeconds,
+	)
+	return i, err
+}
+
+const getFeedByURL = `-- name: GetFeedByURL :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds WHERE url = ?
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURL, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.FetchFullContent,
+		&i.ParseWarnings,
+		&i.RetiredAt,
+		&i.NextFetchAt,
+		&i.Credentials,
+		&i.KeepPosts,
+		&i.KeepForSeconds,
+	)
+	return i, err
+}
+
+const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, fetch_full_content, parse_warnings, retired_at, next_fetch_at, credentials, keep_posts, keep_for_seconds FROM feeds
+    WHERE retired_at IS NULL
+    AND (next_fetch_at IS NULL OR julianday(next_fetch_at) <= julianday(?1))
+    ORDER BY last_fetched_at ASC LIMIT ?2
+`
+
+type GetNextFeedsToFetchParams struct {
+	Now      interface{}
+	MaxFeeds int64
+}
+
+func (q *Queries) GetNextFeedsToFetch(ctx context.Context, arg GetNextFeedsToFetchParams) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getNextFeedsToFetch, arg.Now, arg.MaxFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.FetchFullContent,
+			&i.ParseWarnings,
+			&i.RetiredAt,
+			&i.NextFetchAt,
+			&i.Credentials,
+			&i.KeepPosts,
+			&i.KeepForSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFeedFetched = `-- name: MarkFeedFetched :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	last_fetched_at = ?
+    WHERE id = ?
+`
+
+type MarkFeedFetchedParams struct {
+	UpdatedAt     time.Time
+	LastFetchedAt sql.NullTime
+	ID            uuid.UUID
+}
+
+func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.UpdatedAt, arg.LastFetchedAt, arg.ID)
+	return err
+}
+
+const mergeFeedPosts = `-- name: MergeFeedPosts :exec
+UPDATE posts
+    SET feed_id = ?1
+    WHERE feed_id = ?2
+`
+
+type MergeFeedPostsParams struct {
+	IntoFeedID uuid.UUID
+	FromFeedID uuid.UUID
+}
+
+func (q *Queries) MergeFeedPosts(ctx context.Context, arg MergeFeedPostsParams) error {
+	_, err := q.db.ExecContext(ctx, mergeFeedPosts, arg.IntoFeedID, arg.FromFeedID)
+	return err
+}
+
+const resetFeedFetchState = `-- name: ResetFeedFetchState :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	last_fetched_at = NULL,
+	next_fetch_at = NULL,
+	parse_warnings = '[]'
+`
+
+func (q *Queries) ResetFeedFetchState(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, resetFeedFetchState, updatedAt)
+	return err
+}
+
+const retireFeed = `-- name: RetireFeed :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	retired_at = ?
+    WHERE id = ?
+`
+
+type RetireFeedParams struct {
+	UpdatedAt time.Time
+	RetiredAt sql.NullTime
+	ID        uuid.UUID
+}
+
+func (q *Queries) RetireFeed(ctx context.Context, arg RetireFeedParams) error {
+	_, err := q.db.ExecContext(ctx, retireFeed, arg.UpdatedAt, arg.RetiredAt, arg.ID)
+	return err
+}
+
+const setFeedFetchFullContent = `-- name: SetFeedFetchFullContent :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	fetch_full_content = ?
+    WHERE id = ?
+`
+
+type SetFeedFetchFullContentParams struct {
+	UpdatedAt        time.Time
+	FetchFullContent bool
+	ID               uuid.UUID
+}
+
+func (q *Queries) SetFeedFetchFullContent(ctx context.Context, arg SetFeedFetchFullContentParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchFullContent, arg.UpdatedAt, arg.FetchFullContent, arg.ID)
+	return err
+}
+
+const setFeedNextFetch = `-- name: SetFeedNextFetch :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	next_fetch_at = ?
+    WHERE id = ?
+`
+
+type SetFeedNextFetchParams struct {
+	UpdatedAt   time.Time
+	NextFetchAt sql.NullTime
+	ID          uuid.UUID
+}
+
+func (q *Queries) SetFeedNextFetch(ctx context.Context, arg SetFeedNextFetchParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedNextFetch, arg.UpdatedAt, arg.NextFetchAt, arg.ID)
+	return err
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	user_id = ?
+    WHERE id = ?
+`
+
+type SetFeedOwnerParams struct {
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	ID        uuid.UUID
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedOwner, arg.UpdatedAt, arg.UserID, arg.ID)
+	return err
+}
+
+const setFeedParseWarnings = `-- name: SetFeedParseWarnings :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	parse_warnings = ?
+    WHERE id = ?
+`
+
+type SetFeedParseWarningsParams struct {
+	UpdatedAt     time.Time
+	ParseWarnings stringList
+	ID            uuid.UUID
+}
+
+func (q *Queries) SetFeedParseWarnings(ctx context.Context, arg SetFeedParseWarningsParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedParseWarnings, arg.UpdatedAt, arg.ParseWarnings, arg.ID)
+	return err
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	keep_posts = ?,
+	keep_for_seconds = ?
+    WHERE id = ?
+`
+
+type SetFeedRetentionParams struct {
+	UpdatedAt      time.Time
+	KeepPosts      sql.NullInt32
+	KeepForSeconds sql.NullInt64
+	ID             uuid.UUID
+}
+
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRetention,
+		arg.UpdatedAt,
+		arg.KeepPosts,
+		arg.KeepForSeconds,
+		arg.ID,
+	)
+	return err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	url = ?
+    WHERE id = ?
+`
+
+type UpdateFeedURLParams struct {
+	UpdatedAt time.Time
+	Url       string
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedURL, arg.UpdatedAt, arg.Url, arg.ID)
+	return err
+}
@@ -0,0 +1,118 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: fetches.sql
+
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFetch = `-- name: CreateFetch :exec
+INSERT INTO fetches (id, created_at, feed_id, status_code, bytes_transferred, bytes_decoded, duration_ms, error)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateFetchParams struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	FeedID           uuid.UUID
+	StatusCode       int32
+	BytesTransferred int64
+	BytesDecoded     int64
+	DurationMs       int64
+	Error            string
+}
+
+func (q *Queries) CreateFetch(ctx context.Context, arg CreateFetchParams) error {
+	_, err := q.db.ExecContext(ctx, createFetch,
+		arg.ID,
+		arg.CreatedAt,
+		arg.FeedID,
+		arg.StatusCode,
+		arg.BytesTransferred,
+		arg.BytesDecoded,
+		arg.DurationMs,
+		arg.Error,
+	)
+	return err
+}
+
+const deleteFetches = `-- name: DeleteFetches :exec
+DELETE FROM fetches
+`
+
+func (q *Queries) DeleteFetches(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFetches)
+	return err
+}
+
+const getFetchStats = `-- name: GetFetchStats :many
+SELECT
+	feeds.name,
+	feeds.url,
+	COUNT(*) AS fetches,
+	CAST(SUM(CASE WHEN fetches.status_code <> 200 THEN 1 ELSE 0 END) AS BIGINT) AS failures,
+	CAST(SUM(fetches.bytes_transferred) AS BIGINT) AS bytes_transferred,
+	CAST(SUM(fetches.bytes_decoded) AS BIGINT) AS bytes_decoded,
+	CAST(AVG(fetches.duration_ms) AS BIGINT) AS avg_duration_ms,
+	CAST(MAX(fetches.duration_ms) AS BIGINT) AS max_duration_ms,
+	CAST((
+		SELECT latest.status_code FROM fetches AS latest
+		WHERE latest.feed_id = feeds.id
+		ORDER BY latest.created_at DESC LIMIT 1
+	) AS INTEGER) AS last_status
+FROM fetches
+INNER JOIN feeds ON fetches.feed_id = feeds.id
+WHERE fetches.created_at >= ?
+GROUP BY feeds.id
+`
+
+type GetFetchStatsRow struct {
+	Name             string
+	Url              string
+	Fetches          int64
+	Failures         int64
+	BytesTransferred int64
+	BytesDecoded     int64
+	AvgDurationMs    int64
+	MaxDurationMs    int64
+	LastStatus       int64
+}
+
+func (q *Queries) GetFetchStats(ctx context.Context, createdAt time.Time) ([]GetFetchStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFetchStats, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFetchStatsRow
+	for rows.Next() {
+		var i GetFetchStatsRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Url,
+			&i.Fetches,
+			&i.Failures,
+			&i.BytesTransferred,
+			&i.BytesDecoded,
+			&i.AvgDurationMs,
+			&i.MaxDurationMs,
+			&i.LastStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,99 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Enclosure struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	PostID    uuid.UUID
+	Url       string
+	Length    int64
+	MimeType  string
+	Duration  string
+	Episode   sql.NullInt32
+	Season    sql.NullInt32
+	ImageUrl  string
+}
+
+type Feed struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	LastFetchedAt    sql.NullTime
+	FetchFullContent bool
+	ParseWarnings    stringList
+	RetiredAt        sql.NullTime
+	NextFetchAt      sql.NullTime
+	Credentials      []byte
+	KeepPosts        sql.NullInt32
+	KeepForSeconds   sql.NullInt64
+}
+
+type FeedFollow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+}
+
+type Fetch struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	FeedID           uuid.UUID
+	StatusCode       int32
+	BytesTransferred int64
+	BytesDecoded     int64
+	DurationMs       int64
+	Error            string
+}
+
+type Post struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description string
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+	Content     sql.NullString
+	Authors     stringList
+	Categories  stringList
+	CommentsUrl string
+	ImageUrl    string
+}
+
+type PostState struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Read      bool
+	Starred   bool
+}
+
+type User struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	DisplayName string
+	Email       string
+	Timezone    string
+	BrowseLimit int32
+}
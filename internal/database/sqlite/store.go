@@ -0,0 +1,474 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// stringList stores what Postgres keeps in TEXT[] columns as a JSON array.
+type stringList []string
+
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = stringList{}
+	}
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (l *stringList) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case string:
+		data = []byte(src)
+	case []byte:
+		data = src
+	case nil:
+		*l = stringList{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into string list", src)
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// Store implements database.Querier on top of the SQLite queries, converting
+// to and from the Postgres types the rest of gator uses.
+type Store struct {
+	q  *Queries
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{q: New(db), db: db}
+}
+
+var _ database.Store = (*Store)(nil)
+
+func (s *Store) InTx(ctx context.Context, fn func(database.Querier) error) error {
+	return database.RunInTx(ctx, s.db, func(tx *sql.Tx) error {
+		return fn(&Store{q: s.q.WithTx(tx)})
+	})
+}
+
+func (s *Store) AddFeed(ctx context.Context, arg database.AddFeedParams) (database.Feed, error) {
+	feed, err := s.q.AddFeed(ctx, AddFeedParams(arg))
+	return toFeed(feed), err
+}
+
+func (s *Store) CreateEnclosure(ctx context.Context, arg database.CreateEnclosureParams) error {
+	return s.q.CreateEnclosure(ctx, CreateEnclosureParams(arg))
+}
+
+// CreateFeedFollow inserts the follow and looks up the names separately, as
+// SQLite can't insert inside a WITH clause.
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	follow, err := s.q.CreateFeedFollow(ctx, CreateFeedFollowParams(arg))
+	if err != nil {
+		return database.CreateFeedFollowRow{}, err
+	}
+	names, err := s.q.GetFeedFollowNames(ctx, follow.ID)
+	if err != nil {
+		return database.CreateFeedFollowRow{}, err
+	}
+	return database.CreateFeedFollowRow{
+		ID:        follow.ID,
+		CreatedAt: follow.CreatedAt,
+		UpdatedAt: follow.UpdatedAt,
+		UserID:    follow.UserID,
+		FeedID:    follow.FeedID,
+		UserName:  names.UserName,
+		FeedName:  names.FeedName,
+	}, nil
+}
+
+func (s *Store) CreateFetch(ctx context.Context, arg database.CreateFetchParams) error {
+	return s.q.CreateFetch(ctx, CreateFetchParams(arg))
+}
+
+func (s *Store) CreatePost(ctx context.Context, arg database.CreatePostParams) (int64, error) {
+	return s.q.CreatePost(ctx, CreatePostParams{
+		ID:          arg.ID,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+		Title:       arg.Title,
+		Url:         arg.Url,
+		Description: arg.Description,
+		PublishedAt: arg.PublishedAt,
+		FeedID:      arg.FeedID,
+		Content:     arg.Content,
+		Authors:     arg.Authors,
+		Categories:  arg.Categories,
+		CommentsUrl: arg.CommentsUrl,
+		ImageUrl:    arg.ImageUrl,
+	})
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	user, err := s.q.CreateUser(ctx, CreateUserParams(arg))
+	return database.User(user), err
+}
+
+func (s *Store) DeleteAllFeeds(ctx context.Context) error {
+	return s.q.DeleteAllFeeds(ctx)
+}
+
+func (s *Store) DeleteAllPosts(ctx context.Context) error {
+	return s.q.DeleteAllPosts(ctx)
+}
+
+func (s *Store) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	return s.q.DeleteFeed(ctx, id)
+}
+
+func (s *Store) DeleteFeedFollowsForUser(ctx context.Context, userID uuid.UUID) error {
+	return s.q.DeleteFeedFollowsForUser(ctx, userID)
+}
+
+func (s *Store) DeleteFetches(ctx context.Context) error {
+	return s.q.DeleteFetches(ctx)
+}
+
+func (s *Store) DeletePost(ctx context.Context, id uuid.UUID) error {
+	return s.q.DeletePost(ctx, id)
+}
+
+func (s *Store) DeletePostStatesForUser(ctx context.Context, userID uuid.UUID) error {
+	return s.q.DeletePostStatesForUser(ctx, userID)
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return s.q.DeleteUser(ctx, id)
+}
+
+func (s *Store) Feeds(ctx context.Context) ([]database.Feed, error) {
+	feeds, err := s.q.Feeds(ctx)
+	return convertAll(feeds, toFeed), err
+}
+
+func (s *Store) GetEnclosuresForFeed(ctx context.Context, feedID uuid.UUID) ([]database.GetEnclosuresForFeedRow, error) {
+	rows, err := s.q.GetEnclosuresForFeed(ctx, feedID)
+	return convertAll(rows, func(row GetEnclosuresForFeedRow) database.GetEnclosuresForFeedRow {
+		return database.GetEnclosuresForFeedRow(row)
+	}), err
+}
+
+func (s *Store) GetEnclosuresForPost(ctx context.Context, postID uuid.UUID) ([]database.Enclosure, error) {
+	enclosures, err := s.q.GetEnclosuresForPost(ctx, postID)
+	return convertAll(enclosures, func(enclosure Enclosure) database.Enclosure {
+		return database.Enclosure(enclosure)
+	}), err
+}
+
+func (s *Store) GetFeedByID(ctx context.Context, id uuid.UUID) (database.Feed, error) {
+	feed, err := s.q.GetFeedByID(ctx, id)
+	return toFeed(feed), err
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, url string) (database.Feed, error) {
+	feed, err := s.q.GetFeedByURL(ctx, url)
+	return toFeed(feed), err
+}
+
+func (s *Store) GetFeedFollowsForFeed(ctx context.Context, feedID uuid.UUID) ([]database.FeedFollow, error) {
+	follows, err := s.q.GetFeedFollowsForFeed(ctx, feedID)
+	return convertAll(follows, func(follow FeedFollow) database.FeedFollow {
+		return database.FeedFollow(follow)
+	}), err
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.FeedFollow, error) {
+	follows, err := s.q.GetFeedFollowsForUser(ctx, userID)
+	return convertAll(follows, func(follow FeedFollow) database.FeedFollow {
+		return database.FeedFollow(follow)
+	}), err
+}
+
+func (s *Store) GetFeedsWithUnreadCount(ctx context.Context, userID uuid.UUID) ([]database.GetFeedsWithUnreadCountRow, error) {
+	rows, err := s.q.GetFeedsWithUnreadCount(ctx, userID)
+	return convertAll(rows, func(row GetFeedsWithUnreadCountRow) database.GetFeedsWithUnreadCountRow {
+		return database.GetFeedsWithUnreadCountRow(row)
+	}), err
+}
+
+func (s *Store) GetFetchStats(ctx context.Context, createdAt time.Time) ([]database.GetFetchStatsRow, error) {
+	rows, err := s.q.GetFetchStats(ctx, createdAt)
+	return convertAll(rows, func(row GetFetchStatsRow) database.GetFetchStatsRow {
+		return database.GetFetchStatsRow{
+			Name:             row.Name,
+			Url:              row.Url,
+			Fetches:          row.Fetches,
+			Failures:         row.Failures,
+			BytesTransferred: row.BytesTransferred,
+			BytesDecoded:     row.BytesDecoded,
+			AvgDurationMs:    row.AvgDurationMs,
+			MaxDurationMs:    row.MaxDurationMs,
+			LastStatus:       int32(row.LastStatus),
+		}
+	}), err
+}
+
+func (s *Store) GetNextFeedsToFetch(ctx context.Context, arg database.GetNextFeedsToFetchParams) ([]database.Feed, error) {
+	feeds, err := s.q.GetNextFeedsToFetch(ctx, GetNextFeedsToFetchParams{
+		Now:      arg.Now,
+		MaxFeeds: int64(arg.MaxFeeds),
+	})
+	return convertAll(feeds, toFeed), err
+}
+
+func (s *Store) GetPostByID(ctx context.Context, id uuid.UUID) (database.Post, error) {
+	post, err := s.q.GetPostByID(ctx, id)
+	return toPost(post), err
+}
+
+func (s *Store) GetPostByURL(ctx context.Context, url string) (database.Post, error) {
+	post, err := s.q.GetPostByURL(ctx, url)
+	return toPost(post), err
+}
+
+func (s *Store) GetPostStatesForUser(ctx context.Context, userID uuid.UUID) ([]database.PostState, error) {
+	states, err := s.q.GetPostStatesForUser(ctx, userID)
+	return convertAll(states, func(state PostState) database.PostState {
+		return database.PostState(state)
+	}), err
+}
+
+func (s *Store) GetPostsByFeedID(ctx context.Context, feedID uuid.UUID) ([]database.Post, error) {
+	posts, err := s.q.GetPostsByFeedID(ctx, feedID)
+	return convertAll(posts, toPost), err
+}
+
+func (s *Store) GetPostsByUser(ctx context.Context, arg database.GetPostsByUserParams) ([]database.Post, error) {
+	posts, err := s.q.GetPostsByUser(ctx, GetPostsByUserParams{
+		Name:  arg.Name,
+		Limit: int64(arg.Limit),
+	})
+	return convertAll(posts, toPost), err
+}
+
+func (s *Store) GetPostsForFeed(ctx context.Context, arg database.GetPostsForFeedParams) ([]database.GetPostsForFeedRow, error) {
+	rows, err := s.q.GetPostsForFeed(ctx, GetPostsForFeedParams(arg))
+	return convertAll(rows, func(row GetPostsForFeedRow) database.GetPostsForFeedRow {
+		return database.GetPostsForFeedRow{
+			ID:          row.ID,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			Title:       row.Title,
+			Url:         row.Url,
+			Description: row.Description,
+			PublishedAt: row.PublishedAt,
+			FeedID:      row.FeedID,
+			Content:     row.Content,
+			Authors:     row.Authors,
+			Categories:  row.Categories,
+			CommentsUrl: row.CommentsUrl,
+			ImageUrl:    row.ImageUrl,
+			Read:        row.Read,
+			Starred:     row.Starred,
+		}
+	}), err
+}
+
+func (s *Store) GetPostsForRetention(ctx context.Context, feedID uuid.UUID) ([]database.GetPostsForRetentionRow, error) {
+	rows, err := s.q.GetPostsForRetention(ctx, feedID)
+	return convertAll(rows, func(row GetPostsForRetentionRow) database.GetPostsForRetentionRow {
+		return database.GetPostsForRetentionRow(row)
+	}), err
+}
+
+func (s *Store) GetUser(ctx context.Context, name string) (database.User, error) {
+	user, err := s.q.GetUser(ctx, name)
+	return database.User(user), err
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := s.q.GetUserByID(ctx, id)
+	return database.User(user), err
+}
+
+func (s *Store) GetUsers(ctx context.Context) ([]database.User, error) {
+	users, err := s.q.GetUsers(ctx)
+	return convertAll(users, func(user User) database.User {
+		return database.User(user)
+	}), err
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) error {
+	return s.q.MarkFeedFetched(ctx, MarkFeedFetchedParams{
+		UpdatedAt:     arg.UpdatedAt,
+		LastFetchedAt: arg.LastFetchedAt,
+		ID:            arg.ID,
+	})
+}
+
+func (s *Store) MergeFeedFollows(ctx context.Context, arg database.MergeFeedFollowsParams) error {
+	return s.q.MergeFeedFollows(ctx, MergeFeedFollowsParams(arg))
+}
+
+func (s *Store) MergeFeedPosts(ctx context.Context, arg database.MergeFeedPostsParams) error {
+	return s.q.MergeFeedPosts(ctx, MergeFeedPostsParams(arg))
+}
+
+func (s *Store) RenameUser(ctx context.Context, arg database.RenameUserParams) (database.User, error) {
+	user, err := s.q.RenameUser(ctx, RenameUserParams{
+		UpdatedAt: arg.UpdatedAt,
+		Name:      arg.Name,
+		ID:        arg.ID,
+	})
+	return database.User(user), err
+}
+
+func (s *Store) Reset(ctx context.Context) error {
+	return s.q.Reset(ctx)
+}
+
+func (s *Store) ResetFeedFetchState(ctx context.Context, updatedAt time.Time) error {
+	return s.q.ResetFeedFetchState(ctx, updatedAt)
+}
+
+func (s *Store) RetireFeed(ctx context.Context, arg database.RetireFeedParams) error {
+	return s.q.RetireFeed(ctx, RetireFeedParams{
+		UpdatedAt: arg.UpdatedAt,
+		RetiredAt: arg.RetiredAt,
+		ID:        arg.ID,
+	})
+}
+
+func (s *Store) SetFeedFetchFullContent(ctx context.Context, arg database.SetFeedFetchFullContentParams) error {
+	return s.q.SetFeedFetchFullContent(ctx, SetFeedFetchFullContentParams{
+		UpdatedAt:        arg.UpdatedAt,
+		FetchFullContent: arg.FetchFullContent,
+		ID:               arg.ID,
+	})
+}
+
+func (s *Store) SetFeedNextFetch(ctx context.Context, arg database.SetFeedNextFetchParams) error {
+	return s.q.SetFeedNextFetch(ctx, SetFeedNextFetchParams{
+		UpdatedAt:   arg.UpdatedAt,
+		NextFetchAt: arg.NextFetchAt,
+		ID:          arg.ID,
+	})
+}
+
+func (s *Store) SetFeedOwner(ctx context.Context, arg database.SetFeedOwnerParams) error {
+	return s.q.SetFeedOwner(ctx, SetFeedOwnerParams{
+		UpdatedAt: arg.UpdatedAt,
+		UserID:    arg.UserID,
+		ID:        arg.ID,
+	})
+}
+
+func (s *Store) SetFeedParseWarnings(ctx context.Context, arg database.SetFeedParseWarningsParams) error {
+	return s.q.SetFeedParseWarnings(ctx, SetFeedParseWarningsParams{
+		UpdatedAt:     arg.UpdatedAt,
+		ParseWarnings: arg.ParseWarnings,
+		ID:            arg.ID,
+	})
+}
+
+func (s *Store) SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) error {
+	return s.q.SetFeedRetention(ctx, SetFeedRetentionParams{
+		UpdatedAt:      arg.UpdatedAt,
+		KeepPosts:      arg.KeepPosts,
+		KeepForSeconds: arg.KeepForSeconds,
+		ID:             arg.ID,
+	})
+}
+
+func (s *Store) SetPostRead(ctx context.Context, arg database.SetPostReadParams) error {
+	return s.q.SetPostRead(ctx, SetPostReadParams(arg))
+}
+
+func (s *Store) SetPostStarred(ctx context.Context, arg database.SetPostStarredParams) error {
+	return s.q.SetPostStarred(ctx, SetPostStarredParams(arg))
+}
+
+func (s *Store) UnfollowFeed(ctx context.Context, arg database.UnfollowFeedParams) error {
+	return s.q.UnfollowFeed(ctx, UnfollowFeedParams(arg))
+}
+
+func (s *Store) UpdateFeedURL(ctx context.Context, arg database.UpdateFeedURLParams) error {
+	return s.q.UpdateFeedURL(ctx, UpdateFeedURLParams{
+		UpdatedAt: arg.UpdatedAt,
+		Url:       arg.Url,
+		ID:        arg.ID,
+	})
+}
+
+func (s *Store) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	user, err := s.q.UpdateUserProfile(ctx, UpdateUserProfileParams{
+		UpdatedAt:   arg.UpdatedAt,
+		DisplayName: arg.DisplayName,
+		Email:       arg.Email,
+		Timezone:    arg.Timezone,
+		BrowseLimit: arg.BrowseLimit,
+		ID:          arg.ID,
+	})
+	return database.User(user), err
+}
+
+func toFeed(feed Feed) database.Feed {
+	return database.Feed{
+		ID:               feed.ID,
+		CreatedAt:        feed.CreatedAt,
+		UpdatedAt:        feed.UpdatedAt,
+		Name:             feed.Name,
+		Url:              feed.Url,
+		UserID:           feed.UserID,
+		LastFetchedAt:    feed.LastFetchedAt,
+		FetchFullContent: feed.FetchFullContent,
+		ParseWarnings:    feed.ParseWarnings,
+		RetiredAt:        feed.RetiredAt,
+		NextFetchAt:      feed.NextFetchAt,
+		Credentials:      feed.Credentials,
+		KeepPosts:        feed.KeepPosts,
+		KeepForSeconds:   feed.KeepForSeconds,
+	}
+}
+
+func toPost(post Post) database.Post {
+	return database.Post{
+		ID:          post.ID,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+		Title:       post.Title,
+		Url:         post.Url,
+		Description: post.Description,
+		PublishedAt: post.PublishedAt,
+		FeedID:      post.FeedID,
+		Content:     post.Content,
+		Authors:     post.Authors,
+		Categories:  post.Categories,
+		CommentsUrl: post.CommentsUrl,
+		ImageUrl:    post.ImageUrl,
+	}
+}
+
+func convertAll[T, U any](items []T, convert func(T) U) []U {
+	if items == nil {
+		return nil
+	}
+	converted := make([]U, len(items))
+	for i, item := range items {
+		converted[i] = convert(item)
+	}
+	return converted
+}
+
+// Open opens the SQLite database at path with foreign keys enforced, which
+// the ON DELETE CASCADE clauses rely on, and waits for locks held by other
+// connections instead of failing.
+func Open(path string) (*sql.DB, error) {
+	return sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite")
+}
@@ -0,0 +1,132 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/brendenwelch/gator/internal/database/sqlite"
+	"github.com/brendenwelch/gator/internal/migrate"
+	schema "github.com/brendenwelch/gator/sql/sqlite/schema"
+	"github.com/google/uuid"
+)
+
+func newStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db, migrate.SQLite, schema.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return sqlite.NewStore(db)
+}
+
+func TestGetNextFeedsToFetchHonoursNextFetchAt(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	user, err := store.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := store.AddFeed(ctx, database.AddFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      "blog",
+		Url:       "https://blog.example.com/feed.xml",
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		nextFetchAt sql.NullTime
+		want        int
+	}{
+		{"never backed off", sql.NullTime{}, 1},
+		{"back off over", sql.NullTime{Time: now.Add(-time.Hour), Valid: true}, 1},
+		{"back off over a second ago", sql.NullTime{Time: now.Add(-time.Second), Valid: true}, 1},
+		{"backing off", sql.NullTime{Time: now.Add(time.Hour), Valid: true}, 0},
+		{"backing off for another second", sql.NullTime{Time: now.Add(time.Second), Valid: true}, 0},
+	}
+	for _, tt := range tests {
+		if err := store.SetFeedNextFetch(ctx, database.SetFeedNextFetchParams{
+			ID:          feed.ID,
+			UpdatedAt:   now,
+			NextFetchAt: tt.nextFetchAt,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		feeds, err := store.GetNextFeedsToFetch(ctx, database.GetNextFeedsToFetchParams{Now: now, MaxFeeds: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(feeds) != tt.want {
+			t.Errorf("%v: got %v feeds to fetch, want %v", tt.name, len(feeds), tt.want)
+		}
+	}
+}
+
+func TestDeleteUserKeepsOwnedFeeds(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	var users []database.User
+	for _, name := range []string{"alice", "bob"} {
+		user, err := store.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user)
+	}
+	alice, bob := users[0], users[1]
+	feed, err := store.AddFeed(ctx, database.AddFeedParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      "blog",
+		Url:       "https://blog.example.com/feed.xml",
+		UserID:    alice.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    bob.ID,
+		FeedID:    feed.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteUser(ctx, alice.ID); err == nil {
+		t.Fatal("deleted a user who still owns a feed")
+	}
+	if follows, err := store.GetFeedFollowsForFeed(ctx, feed.ID); err != nil || len(follows) != 1 {
+		t.Fatalf("follows after the refused delete: %v, %v", follows, err)
+	}
+
+	if err := store.SetFeedOwner(ctx, database.SetFeedOwnerParams{ID: feed.ID, UpdatedAt: now, UserID: bob.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteUser(ctx, alice.ID); err != nil {
+		t.Errorf("deleting a user without feeds failed: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Store is a Querier that can also run a group of queries as one
+// transaction.
+type Store interface {
+	Querier
+	// InTx runs fn with a Querier bound to a new transaction. The transaction
+	// is committed if fn returns nil and rolled back otherwise.
+	InTx(ctx context.Context, fn func(Querier) error) error
+}
+
+type store struct {
+	*Queries
+	db *sql.DB
+}
+
+// NewStore returns a Store for the Postgres database db.
+func NewStore(db *sql.DB) Store {
+	return &store{Queries: New(db), db: db}
+}
+
+func (s *store) InTx(ctx context.Context, fn func(Querier) error) error {
+	return RunInTx(ctx, s.db, func(tx *sql.Tx) error {
+		return fn(s.WithTx(tx))
+	})
+}
+
+// RunInTx begins a transaction on db, runs fn in it and commits it if fn
+// returns nil.
+func RunInTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
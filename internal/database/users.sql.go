@@ -20,7 +20,7 @@ INSERT INTO users (id, created_at, updated_at, name)
     $3,
     $4
   )
-  RETURNING id, created_at, updated_at, name
+  RETURNING id, created_at, updated_at, name, display_name, email, timezone, browse_limit
 `
 
 type CreateUserParams struct {
@@ -43,12 +43,25 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.DisplayName,
+		&i.Email,
+		&i.Timezone,
+		&i.BrowseLimit,
 	)
 	return i, err
 }
 
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
 const getUser = `-- name: GetUser :one
-SELECT id, created_at, updated_at, name FROM users
+SELECT id, created_at, updated_at, name, display_name, email, timezone, browse_limit FROM users
   WHERE name = $1
   LIMIT 1
 `
@@ -61,12 +74,16 @@ func (q *Queries) GetUser(ctx context.Context, name string) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.DisplayName,
+		&i.Email,
+		&i.Timezone,
+		&i.BrowseLimit,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, name FROM users
+SELECT id, created_at, updated_at, name, display_name, email, timezone, browse_limit FROM users
   WHERE id = $1
   LIMIT 1
 `
@@ -79,12 +96,16 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.DisplayName,
+		&i.Email,
+		&i.Timezone,
+		&i.BrowseLimit,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, created_at, updated_at, name FROM users
+SELECT id, created_at, updated_at, name, display_name, email, timezone, browse_limit FROM users
 `
 
 func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
@@ -101,6 +122,10 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Name,
+			&i.DisplayName,
+			&i.Email,
+			&i.Timezone,
+			&i.BrowseLimit,
 		); err != nil {
 			return nil, err
 		}
@@ -115,6 +140,35 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const renameUser = `-- name: RenameUser :one
+UPDATE users
+  SET updated_at = $2, name = $3
+  WHERE id = $1
+  RETURNING id, created_at, updated_at, name, display_name, email, timezone, browse_limit
+`
+
+type RenameUserParams struct {
+	ID        uuid.UUID
+	UpdatedAt time.Time
+	Name      string
+}
+
+func (q *Queries) RenameUser(ctx context.Context, arg RenameUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, renameUser, arg.ID, arg.UpdatedAt, arg.Name)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.DisplayName,
+		&i.Email,
+		&i.Timezone,
+		&i.BrowseLimit,
+	)
+	return i, err
+}
+
 const reset = `-- name: Reset :exec
 DELETE FROM users
 `
@@ -123,3 +177,47 @@ func (q *Queries) Reset(ctx context.Context) error {
 	_, err := q.db.ExecContext(ctx, reset)
 	return err
 }
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+  SET
+    updated_at = $2,
+    display_name = $3,
+    email = $4,
+    timezone = $5,
+    browse_limit = $6
+  WHERE id = $1
+  RETURNING id, created_at, updated_at, name, display_name, email, timezone, browse_limit
+`
+
+type UpdateUserProfileParams struct {
+	ID          uuid.UUID
+	UpdatedAt   time.Time
+	DisplayName string
+	Email       string
+	Timezone    string
+	BrowseLimit int32
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.DisplayName,
+		arg.Email,
+		arg.Timezone,
+		arg.BrowseLimit,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.DisplayName,
+		&i.Email,
+		&i.Timezone,
+		&i.BrowseLimit,
+	)
+	return i, err
+}
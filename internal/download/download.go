@@ -0,0 +1,155 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stallTimeout is how long a download may go without receiving any data
+// before it is abandoned. Downloads have no overall deadline, since a long
+// episode on a slow link can take a while.
+var stallTimeout = time.Minute
+
+var errStalled = errors.New("download stalled")
+
+// File downloads url to path. Data is written to path+".part" first and only
+// renamed once complete, so an interrupted download is resumed with a range
+// request the next time File is called.
+func File(ctx context.Context, client *http.Client, url, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	part := path + ".part"
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %v: %w", part, err)
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to seek %v: %w", part, err)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stall := time.AfterFunc(stallTimeout, func() { cancel(errStalled) })
+	defer stall.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, errStalled) {
+			err = cause
+		}
+		return fmt.Errorf("download request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		if start, ok := rangeStart(res.Header.Get("Content-Range")); !ok || start != offset {
+			// Appending would corrupt the file, so start over next time.
+			if err := f.Truncate(0); err != nil {
+				return fmt.Errorf("failed to truncate %v: %w", part, err)
+			}
+			return fmt.Errorf("download of %v resumed at the wrong offset (Content-Range %q, want %v), try again", url, res.Header.Get("Content-Range"), offset)
+		}
+	case http.StatusOK:
+		// The server ignored the range, so start over.
+		if err := f.Truncate(0); err != nil {
+			return fmt.Errorf("failed to truncate %v: %w", part, err)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek %v: %w", part, err)
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset == 0 {
+			return fmt.Errorf("download request failed: %v", res.Status)
+		}
+		// The partial file already holds everything, unless the remote file
+		// changed size since it was started.
+		if size, ok := rangeSize(res.Header.Get("Content-Range")); !ok || size != offset {
+			if err := f.Truncate(0); err != nil {
+				return fmt.Errorf("failed to truncate %v: %w", part, err)
+			}
+			return fmt.Errorf("download of %v no longer matches the partial file (Content-Range %q, have %v bytes), try again", url, res.Header.Get("Content-Range"), offset)
+		}
+	default:
+		return fmt.Errorf("download request failed: %v", res.Status)
+	}
+
+	if res.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		body := &progressReader{r: res.Body, progress: func() { stall.Reset(stallTimeout) }}
+		if _, err := io.Copy(f, body); err != nil {
+			if cause := context.Cause(ctx); errors.Is(cause, errStalled) {
+				err = cause
+			}
+			return fmt.Errorf("failed to download %v: %w", url, err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %v: %w", part, err)
+	}
+	if err := os.Rename(part, path); err != nil {
+		return fmt.Errorf("failed to rename %v: %w", part, err)
+	}
+	return nil
+}
+
+// rangeStart returns the first byte position of a Content-Range header like
+// "bytes 100-199/200".
+func rangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// rangeSize returns the complete length from the Content-Range header of a
+// 416 response, like "bytes */200".
+func rangeSize(header string) (int64, bool) {
+	size, ok := strings.CutPrefix(header, "bytes */")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+type progressReader struct {
+	r        io.Reader
+	progress func()
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.progress()
+	}
+	return n, err
+}
@@ -0,0 +1,126 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const episode = "0123456789abcdefghij"
+
+func TestFileResumes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "episode.mp3", time.Time{}, strings.NewReader(episode))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	if err := os.WriteFile(path+".part", []byte(episode[:8]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := File(context.Background(), server.Client(), server.URL, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != episode {
+		t.Errorf("downloaded %q, want %q", data, episode)
+	}
+}
+
+func TestFileRejectsWrongContentRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Resume from the start, whatever was asked for.
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%v/%v", len(episode)-1, len(episode)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(episode))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "episode.mp3")
+	if err := os.WriteFile(path+".part", []byte(episode[:8]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := File(context.Background(), server.Client(), server.URL, path); err == nil {
+		t.Fatal("expected an error for a range that doesn't start at the offset")
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("a corrupt download was saved")
+	}
+	if info, err := os.Stat(path + ".part"); err != nil || info.Size() != 0 {
+		t.Errorf("partial file was not reset: %v, %v", info, err)
+	}
+}
+
+func TestFileUnsatisfiableRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote string
+		saved  bool
+	}{
+		{"part file is complete", episode, true},
+		{"remote file shrank", episode[:10], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "episode.mp3", time.Time{}, strings.NewReader(tt.remote))
+			}))
+			defer server.Close()
+			path := filepath.Join(t.TempDir(), "episode.mp3")
+			if err := os.WriteFile(path+".part", []byte(episode), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := File(context.Background(), server.Client(), server.URL, path)
+			if tt.saved {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if data, err := os.ReadFile(path); err != nil || string(data) != episode {
+					t.Errorf("saved %q, %v; want the partial file", data, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error when the remote file no longer matches")
+			}
+			if _, err := os.Stat(path); err == nil {
+				t.Error("a truncated download was saved")
+			}
+			if info, err := os.Stat(path + ".part"); err != nil || info.Size() != 0 {
+				t.Errorf("partial file was not reset: %v, %v", info, err)
+			}
+		})
+	}
+}
+
+func TestFileGivesUpWhenStalled(t *testing.T) {
+	stallTimeout = 50 * time.Millisecond
+	defer func() { stallTimeout = time.Minute }()
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(episode[:4]))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	err := File(context.Background(), server.Client(), server.URL, filepath.Join(t.TempDir(), "episode.mp3"))
+	if !errors.Is(err, errStalled) {
+		t.Errorf("stalled download returned %v", err)
+	}
+}
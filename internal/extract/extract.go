@@ -0,0 +1,262 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const maxPageSize = 5 << 20
+
+var (
+	unlikely = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|foot|header|legends|menu|modal|nav|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tags|tool|widget|^ad-|-ad$|\bad\b`)
+	positive = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|story|text|blog`)
+	negative = regexp.MustCompile(`(?i)hidden|^hid$|hid$|caption|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+type Page struct {
+	// Content is the HTML of the main article.
+	Content string
+	// Image is the page's og:image or twitter:image, if any.
+	Image string
+}
+
+// Article downloads pageURL with client and extracts its main content.
+func Article(ctx context.Context, client *http.Client, pageURL string) (Page, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to create article request: %w", err)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return Page{}, fmt.Errorf("article request failed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("article request failed: %v", res.Status)
+	}
+	return Extract(io.LimitReader(res.Body, maxPageSize))
+}
+
+// Extract finds the element of an HTML page most likely to hold the article
+// body, scoring blocks of text the way Readability does, and returns it
+// together with any siblings that look like part of the same article.
+func Extract(r io.Reader) (Page, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to parse article: %w", err)
+	}
+	page := Page{Image: metaImage(doc)}
+	body := find(doc, atom.Body)
+	if body == nil {
+		return page, fmt.Errorf("article has no body")
+	}
+	prune(body)
+
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+	walk(body, func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		default:
+			return
+		}
+		text := textContent(n)
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		for i, ancestor := 0, n.Parent; i < 3 && ancestor != nil && ancestor.Type == html.ElementNode; i, ancestor = i+1, ancestor.Parent {
+			if _, ok := scores[ancestor]; !ok {
+				scores[ancestor] = classWeight(ancestor) + tagWeight(ancestor)
+				candidates = append(candidates, ancestor)
+			}
+			scores[ancestor] += score / float64(1+i*i)
+		}
+	})
+
+	var top *html.Node
+	for _, c := range candidates {
+		scores[c] *= 1 - linkDensity(c)
+		if top == nil || scores[c] > scores[top] {
+			top = c
+		}
+	}
+	if top == nil {
+		return page, fmt.Errorf("no article content found")
+	}
+
+	threshold := math.Max(10, scores[top]*0.2)
+	var b strings.Builder
+	for n := top.Parent.FirstChild; n != nil; n = n.NextSibling {
+		include := n == top
+		if !include && n.Type == html.ElementNode {
+			if score, ok := scores[n]; ok && score >= threshold {
+				include = true
+			} else if n.DataAtom == atom.P {
+				text := textContent(n)
+				density := linkDensity(n)
+				include = (len(text) > 80 && density < 0.25) ||
+					(len(text) > 0 && density == 0 && strings.ContainsAny(text, ".!?"))
+			}
+		}
+		if include {
+			if err := html.Render(&b, n); err != nil {
+				return page, fmt.Errorf("failed to render article: %w", err)
+			}
+		}
+	}
+	page.Content = b.String()
+	return page, nil
+}
+
+func metaImage(doc *html.Node) string {
+	var image string
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom != atom.Meta || image != "" {
+			return
+		}
+		switch attr(n, "property") + attr(n, "name") {
+		case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+			image = strings.TrimSpace(attr(n, "content"))
+		}
+	})
+	return image
+}
+
+// prune removes elements that never hold article text, and those whose class
+// or id marks them as page furniture.
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode {
+			n.RemoveChild(c)
+		} else if c.Type == html.ElementNode {
+			switch c.DataAtom {
+			case atom.Script, atom.Style, atom.Noscript, atom.Iframe, atom.Form,
+				atom.Nav, atom.Aside, atom.Footer, atom.Header, atom.Button, atom.Select, atom.Svg:
+				n.RemoveChild(c)
+			default:
+				if c.DataAtom != atom.Body && c.DataAtom != atom.Article && furniture(c) {
+					n.RemoveChild(c)
+				} else {
+					prune(c)
+				}
+			}
+		}
+		c = next
+	}
+}
+
+// furniture reports whether n's class names or id mark it as page furniture
+// rather than content. Each is matched on its own, so anchored patterns like
+// ^ad- and -ad$ apply to every one.
+func furniture(n *html.Node) bool {
+	names := append(strings.Fields(attr(n, "class")), attr(n, "id"))
+	var isUnlikely, isPositive bool
+	for _, name := range names {
+		isUnlikely = isUnlikely || unlikely.MatchString(name)
+		isPositive = isPositive || positive.MatchString(name)
+	}
+	return isUnlikely && !isPositive
+}
+
+func classWeight(n *html.Node) float64 {
+	var weight float64
+	for _, s := range []string{attr(n, "class"), attr(n, "id")} {
+		if s == "" {
+			continue
+		}
+		if negative.MatchString(s) {
+			weight -= 25
+		}
+		if positive.MatchString(s) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func tagWeight(n *html.Node) float64 {
+	switch n.DataAtom {
+	case atom.Article:
+		return 10
+	case atom.Div:
+		return 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		return 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		return -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		return -5
+	}
+	return 0
+}
+
+func linkDensity(n *html.Node) float64 {
+	total := len(textContent(n))
+	if total == 0 {
+		return 0
+	}
+	var links int
+	walk(n, func(c *html.Node) {
+		if c.DataAtom == atom.A {
+			links += len(textContent(c))
+		}
+	})
+	return float64(links) / float64(total)
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// walk calls f for every element below n, not including n itself.
+func walk(n *html.Node, f func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			f(c)
+			walk(c, f)
+		}
+	}
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
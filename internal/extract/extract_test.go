@@ -0,0 +1,81 @@
+package extract
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func extractFixture(t *testing.T, name string) (Page, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return Extract(f)
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		fixture string
+		image   string
+		want    []string
+		dropped []string
+	}{
+		{
+			fixture: "teaser.html",
+			image:   "https://blog.example.com/images/walk.jpg",
+			want: []string{
+				"We set out early in the morning",
+				"By noon the path had turned to mud",
+				"In the evening we reached the village",
+				// A sibling of the article body that reads like part of it.
+				"Thanks for reading!",
+			},
+			dropped: []string{"Another story you might like", "And one more story", "/tags/walking"},
+		},
+		{
+			fixture: "furniture.html",
+			image:   "https://blog.example.com/images/chair.jpg",
+			want: []string{
+				"The article starts here",
+				"The article ends here",
+				"only has ad inside a longer class name",
+			},
+			dropped: []string{"Home, about", "sidebar has its own", "Buy something now", "Another advert", "A third advert", "First!", "Copyright"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			page, err := extractFixture(t, tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if page.Image != tt.image {
+				t.Errorf("image is %q, want %q", page.Image, tt.image)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(page.Content, want) {
+					t.Errorf("content is missing %q:\n%v", want, page.Content)
+				}
+			}
+			for _, dropped := range tt.dropped {
+				if strings.Contains(page.Content, dropped) {
+					t.Errorf("content kept %q:\n%v", dropped, page.Content)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractWithoutBody(t *testing.T) {
+	page, err := extractFixture(t, "nobody.html")
+	if err == nil {
+		t.Errorf("extracted %q from a page with no body", page.Content)
+	}
+	if page.Image != "https://blog.example.com/images/empty.jpg" {
+		t.Errorf("image is %q, want the og:image even without an article", page.Image)
+	}
+}
@@ -0,0 +1,51 @@
+// Package feedtest serves fixture feeds over HTTP for tests, so feeds can be
+// fetched without network access.
+package feedtest
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Server is an httptest.Server serving the files in fixtures, e.g. /rss.xml
+// and /atom.xml, and counting the requests for each path.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func NewServer() *Server {
+	files, err := fs.Sub(fixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{requests: map[string]int{}}
+	fileServer := http.FileServerFS(files)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		s.mu.Unlock()
+		fileServer.ServeHTTP(w, r)
+	}))
+	return s
+}
+
+// FeedURL returns the URL a fixture is served at.
+func (s *Server) FeedURL(name string) string {
+	return s.URL + "/" + name
+}
+
+// Requests returns how many times path has been requested.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
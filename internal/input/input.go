@@ -0,0 +1,67 @@
+// Package input shares standard input between the readers that take turns
+// with it, such as the shell's line editor and the reader TUI. A single
+// goroutine reads the underlying file for the life of the process, so a
+// reader that stops early can give up its read without leaving a goroutine
+// behind that swallows the next keystroke.
+package input
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+type Reader struct {
+	src    io.Reader
+	start  sync.Once
+	chunks chan []byte
+	err    error
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+func New(r io.Reader) *Reader {
+	return &Reader{src: r, chunks: make(chan []byte)}
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.ReadContext(context.Background(), p)
+}
+
+// ReadContext is Read that gives up with ctx's error once ctx is done. Input
+// that arrives later is kept for the next read.
+func (r *Reader) ReadContext(ctx context.Context, p []byte) (int, error) {
+	r.start.Do(func() { go r.pump() })
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 {
+		select {
+		case chunk, ok := <-r.chunks:
+			if !ok {
+				return 0, r.err
+			}
+			r.pending = chunk
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *Reader) pump() {
+	for {
+		buf := make([]byte, 256)
+		n, err := r.src.Read(buf)
+		if n > 0 {
+			r.chunks <- buf[:n]
+		}
+		if err != nil {
+			r.err = err
+			close(r.chunks)
+			return
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCancelledReadKeepsInput(t *testing.T) {
+	pr, pw := io.Pipe()
+	r := New(pr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf := make([]byte, 16)
+	if _, err := r.ReadContext(ctx, buf); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled read returned %v", err)
+	}
+
+	go func() {
+		pw.Write([]byte("q"))
+		pw.Close()
+	}()
+	n, err := r.Read(buf)
+	if err != nil || string(buf[:n]) != "q" {
+		t.Fatalf("next read got %q, %v; want the key typed after the cancelled read", buf[:n], err)
+	}
+	if _, err := r.Read(buf); err != io.EOF {
+		t.Errorf("read after close returned %v, want EOF", err)
+	}
+}
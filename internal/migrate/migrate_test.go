@@ -0,0 +1,112 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/brendenwelch/gator/internal/database/sqlite"
+	"github.com/brendenwelch/gator/internal/migrate"
+	schema "github.com/brendenwelch/gator/sql/sqlite/schema"
+)
+
+func newMigrator(t *testing.T) (*migrate.Migrator, *sql.DB) {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migrate.New(db, migrate.SQLite, schema.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrator, db
+}
+
+func wantVersion(t *testing.T, migrator *migrate.Migrator, want int64) {
+	t.Helper()
+	version, err := migrator.Version(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != want {
+		t.Errorf("schema is at version %v, want %v", version, want)
+	}
+}
+
+func hasColumn(t *testing.T, db *sql.DB, table, column string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func TestUpDownTo(t *testing.T) {
+	migrator, db := newMigrator(t)
+	ctx := context.Background()
+	latest := migrator.Latest()
+
+	wantVersion(t, migrator, 0)
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantVersion(t, migrator, latest)
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			t.Errorf("%v is pending after up", status.Name)
+		}
+	}
+
+	if err := migrator.Down(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantVersion(t, migrator, latest-1)
+	if statuses, err = migrator.Status(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if last := statuses[len(statuses)-1]; last.Applied {
+		t.Errorf("%v is still applied after down", last.Name)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantVersion(t, migrator, latest)
+
+	// 004 adds the profile columns to users.
+	if err := migrator.To(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	wantVersion(t, migrator, 3)
+	if hasColumn(t, db, "users", "display_name") {
+		t.Error("users.display_name survived rolling back to 3")
+	}
+	if err := migrator.To(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	wantVersion(t, migrator, 4)
+	if !hasColumn(t, db, "users", "display_name") {
+		t.Error("users.display_name is missing after migrating to 4")
+	}
+
+	if err := migrator.To(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	wantVersion(t, migrator, 0)
+	if hasColumn(t, db, "users", "id") {
+		t.Error("users survived rolling back every migration")
+	}
+	if err := migrator.Down(ctx); err == nil {
+		t.Error("down succeeded with nothing to roll back")
+	}
+	if err := migrator.To(ctx, latest+1); err == nil {
+		t.Error("migrating to a version that doesn't exist succeeded")
+	}
+}
@@ -0,0 +1,373 @@
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	bold      = "\x1b[1m"
+	italic    = "\x1b[3m"
+	underline = "\x1b[4m"
+	dim       = "\x1b[2m"
+	cyan      = "\x1b[36m"
+	reset     = "\x1b[0m"
+)
+
+type Options struct {
+	// Width is the column to wrap text at. Zero means 80.
+	Width int
+	// Color enables ANSI styling of headings, emphasis, links and code.
+	Color bool
+	// BaseURL is used to resolve relative link targets.
+	BaseURL string
+}
+
+type renderer struct {
+	opts  Options
+	base  *url.URL
+	lines []string
+	links []string
+
+	inline  strings.Builder
+	prefix  []string
+	marker  string
+	markAt  int
+	styles  []string
+	lists   []int
+	pre     int
+	pending bool
+}
+
+// Render converts an HTML fragment into wrapped plain text. Links are
+// numbered inline and listed as footnotes at the end.
+func Render(src string, opts Options) string {
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+	r := &renderer{opts: opts}
+	if opts.BaseURL != "" {
+		r.base, _ = url.Parse(opts.BaseURL)
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return strings.TrimSpace(src)
+	}
+	for _, n := range nodes {
+		r.walk(n)
+	}
+	r.flush()
+
+	if len(r.links) > 0 {
+		r.blank()
+		for i, link := range r.links {
+			r.lines = append(r.lines, r.style(fmt.Sprintf("[%v]", i+1), dim)+" "+link)
+		}
+	}
+	for len(r.lines) > 0 && r.lines[len(r.lines)-1] == "" {
+		r.lines = r.lines[:len(r.lines)-1]
+	}
+	return strings.Join(r.lines, "\n")
+}
+
+func (r *renderer) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		r.children(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Iframe, atom.Noscript:
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.block()
+		level := int(n.Data[1] - '0')
+		if !r.opts.Color {
+			r.inline.WriteString(strings.Repeat("#", level) + " ")
+		}
+		r.styled(n, bold+underline)
+		r.block()
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.Figure, atom.Figcaption, atom.Table, atom.Tr, atom.Dl, atom.Dd, atom.Dt:
+		r.block()
+		r.children(n)
+		r.block()
+	case atom.Br:
+		r.flush()
+	case atom.Hr:
+		r.block()
+		r.lines = append(r.lines, r.style(strings.Repeat("─", min(r.width(), 40)), dim))
+		r.block()
+	case atom.Blockquote:
+		r.block()
+		r.prefix = append(r.prefix, "│ ")
+		r.children(n)
+		r.block()
+		r.prefix = r.prefix[:len(r.prefix)-1]
+	case atom.Ul, atom.Ol:
+		if len(r.lists) == 0 {
+			r.block()
+		} else {
+			r.flush()
+		}
+		start := 0
+		if n.DataAtom == atom.Ul {
+			start = -1
+		}
+		r.lists = append(r.lists, start)
+		r.children(n)
+		r.lists = r.lists[:len(r.lists)-1]
+		r.flush()
+		if len(r.lists) == 0 {
+			r.blank()
+		}
+	case atom.Li:
+		r.flush()
+		marker := "• "
+		if len(r.lists) > 0 && r.lists[len(r.lists)-1] >= 0 {
+			r.lists[len(r.lists)-1]++
+			marker = fmt.Sprintf("%v. ", r.lists[len(r.lists)-1])
+		}
+		// The marker takes the place of this item's indent on its first
+		// line, even if the item starts with a quote or code block.
+		r.marker, r.markAt = marker, len(r.prefix)
+		r.prefix = append(r.prefix, strings.Repeat(" ", utf8.RuneCountInString(marker)))
+		r.children(n)
+		r.flush()
+		r.marker = ""
+		r.prefix = r.prefix[:len(r.prefix)-1]
+	case atom.Pre:
+		r.block()
+		r.pre++
+		r.children(n)
+		r.pre--
+		r.flushPre()
+		r.blank()
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		if r.pre > 0 {
+			r.children(n)
+			return
+		}
+		if r.opts.Color {
+			r.styled(n, cyan)
+			return
+		}
+		r.inline.WriteString("`")
+		r.children(n)
+		r.inline.WriteString("`")
+	case atom.B, atom.Strong:
+		r.styled(n, bold)
+	case atom.I, atom.Em, atom.Cite:
+		r.styled(n, italic)
+	case atom.U, atom.Ins:
+		r.styled(n, underline)
+	case atom.A:
+		href := r.resolve(attr(n, "href"))
+		if href == "" {
+			r.children(n)
+			return
+		}
+		r.styled(n, underline)
+		r.inline.WriteString(r.style(fmt.Sprintf("[%v]", r.link(href)), dim))
+	case atom.Img:
+		alt := strings.TrimSpace(attr(n, "alt"))
+		if alt == "" {
+			return
+		}
+		r.text(" [image: " + alt + "] ")
+	case atom.Td, atom.Th:
+		r.children(n)
+		r.text(" ")
+	default:
+		r.children(n)
+	}
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.walk(c)
+	}
+}
+
+func (r *renderer) styled(n *html.Node, code string) {
+	if !r.opts.Color {
+		r.children(n)
+		return
+	}
+	r.styles = append(r.styles, code)
+	r.inline.WriteString(code)
+	r.children(n)
+	r.styles = r.styles[:len(r.styles)-1]
+	r.inline.WriteString(reset + strings.Join(r.styles, ""))
+}
+
+func (r *renderer) style(s, code string) string {
+	if !r.opts.Color {
+		return s
+	}
+	return code + s + reset
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// Clean removes control characters other than newlines and tabs from s, so
+// text taken from a feed can't move the cursor, clear the screen or retitle
+// the terminal it is printed to.
+func Clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func (r *renderer) text(s string) {
+	s = Clean(s)
+	if r.pre > 0 {
+		r.inline.WriteString(s)
+		return
+	}
+	r.inline.WriteString(whitespace.ReplaceAllString(s, " "))
+}
+
+func (r *renderer) link(href string) int {
+	href = Clean(href)
+	for i, link := range r.links {
+		if link == href {
+			return i + 1
+		}
+	}
+	r.links = append(r.links, href)
+	return len(r.links)
+}
+
+func (r *renderer) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return ""
+	}
+	if r.base == nil {
+		return href
+	}
+	u, err := r.base.Parse(href)
+	if err != nil {
+		return href
+	}
+	return u.String()
+}
+
+func (r *renderer) width() int {
+	w := r.opts.Width
+	for _, p := range r.prefix {
+		w -= utf8.RuneCountInString(p)
+	}
+	return max(w, 10)
+}
+
+// block ends the current paragraph and separates it from what follows with
+// a blank line.
+func (r *renderer) block() {
+	r.flush()
+	r.pending = true
+}
+
+func (r *renderer) blank() {
+	if len(r.lines) > 0 && r.lines[len(r.lines)-1] != "" {
+		r.lines = append(r.lines, "")
+	}
+	r.pending = false
+}
+
+func (r *renderer) emit(line string) {
+	if r.pending {
+		r.blank()
+	}
+	var p strings.Builder
+	for i, segment := range r.prefix {
+		if r.marker != "" && i == r.markAt {
+			segment = r.marker
+		}
+		p.WriteString(segment)
+	}
+	r.marker = ""
+	r.lines = append(r.lines, strings.TrimRight(p.String()+line, " "))
+}
+
+func (r *renderer) flush() {
+	text := strings.TrimSpace(r.inline.String())
+	r.inline.Reset()
+	if visibleLen(text) == 0 {
+		return
+	}
+	for _, line := range wrap(text, r.width()) {
+		r.emit(line)
+	}
+}
+
+func (r *renderer) flushPre() {
+	text := strings.Trim(r.inline.String(), "\n")
+	r.inline.Reset()
+	if text == "" {
+		return
+	}
+	r.prefix = append(r.prefix, "    ")
+	for _, line := range strings.Split(text, "\n") {
+		r.emit(r.style(strings.ReplaceAll(line, "\t", "    "), cyan))
+	}
+	r.prefix = r.prefix[:len(r.prefix)-1]
+}
+
+var ansi = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(ansi.ReplaceAllString(s, ""))
+}
+
+func wrap(s string, width int) []string {
+	var lines []string
+	line, lineLen := "", 0
+	for _, word := range strings.Split(s, " ") {
+		wordLen := visibleLen(word)
+		switch {
+		case wordLen == 0:
+			line += word
+		case lineLen == 0:
+			line, lineLen = line+word, wordLen
+		case lineLen+1+wordLen <= width:
+			line, lineLen = line+" "+word, lineLen+1+wordLen
+		default:
+			lines = append(lines, line)
+			line, lineLen = word, wordLen
+		}
+	}
+	if lineLen > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
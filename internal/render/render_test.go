@@ -0,0 +1,79 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDropsControlCharacters(t *testing.T) {
+	out := Render(`<p>&#27;]0;pwned&#7;before&#27;[2Jafter<a href="https://example.com/&#27;[1m">x</a></p><pre>a`+"\u009b"+`b</pre>`, Options{})
+	if strings.ContainsAny(out, "\x1b\x07\u009b") {
+		t.Errorf("rendered text kept control characters: %q", out)
+	}
+	for _, want := range []string{"]0;pwnedbefore[2Jafter", "https://example.com/[1m", "ab"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered text is missing %q: %q", want, out)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	if got := Clean("title\x1b[31m\r\nline\there\u0085"); got != "title[31m\nline\there" {
+		t.Errorf("Clean returned %q", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		opts Options
+		want string
+	}{
+		{
+			name: "heading",
+			src:  `<h2>Title</h2><p>Body</p>`,
+			want: "## Title\n\nBody",
+		},
+		{
+			name: "heading with color",
+			src:  `<h2>Title</h2><p>Body</p>`,
+			opts: Options{Color: true},
+			want: "\x1b[1m\x1b[4mTitle\x1b[0m\n\nBody",
+		},
+		{
+			name: "ordered and nested lists",
+			src:  `<ol><li>one</li><li>two<ul><li>nested</li></ul></li><li>three</li></ol>`,
+			want: "1. one\n2. two\n   • nested\n3. three",
+		},
+		{
+			name: "list item holding a blockquote",
+			src:  `<ul><li><blockquote>quoted text</blockquote></li></ul>`,
+			want: "• │ quoted text",
+		},
+		{
+			name: "list item holding pre",
+			src:  `<ol><li><pre>code line</pre></li></ol>`,
+			want: "1.     code line",
+		},
+		{
+			name: "link footnotes",
+			src:  `<p>See <a href="/about">about</a> and <a href="https://other.example/x">x</a> and <a href="/about">again</a>.</p>`,
+			opts: Options{BaseURL: "https://blog.example.com/posts/1"},
+			want: "See about[1] and x[2] and again[1].\n\n[1] https://blog.example.com/about\n[2] https://other.example/x",
+		},
+		{
+			name: "wrapping",
+			src:  `<p>one two three four five six seven eight nine ten</p>`,
+			opts: Options{Width: 14},
+			want: "one two three\nfour five six\nseven eight\nnine ten",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src, tt.opts); got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
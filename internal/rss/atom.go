@@ -0,0 +1,116 @@
+package rss
+
+import "encoding/xml"
+
+// AtomFeed covers enough of Atom to read feeds like YouTube channels, which
+// are converted to an RSSFeed so the rest of gator only deals with one shape.
+type AtomFeed struct {
+	Title    string      `xml:"http://www.w3.org/2005/Atom title"`
+	Subtitle string      `xml:"http://www.w3.org/2005/Atom subtitle"`
+	Link     []AtomLink  `xml:"http://www.w3.org/2005/Atom link"`
+	Entry    []AtomEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type AtomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// String returns the text, keeping markup for xhtml content which is embedded
+// as elements rather than escaped.
+func (t AtomText) String() string {
+	if t.Type == "xhtml" {
+		return t.Inner
+	}
+	return t.Text
+}
+
+type AtomEntry struct {
+	Title     string     `xml:"http://www.w3.org/2005/Atom title"`
+	Link      []AtomLink `xml:"http://www.w3.org/2005/Atom link"`
+	Summary   AtomText   `xml:"http://www.w3.org/2005/Atom summary"`
+	Content   AtomText   `xml:"http://www.w3.org/2005/Atom content"`
+	Published string     `xml:"http://www.w3.org/2005/Atom published"`
+	Updated   string     `xml:"http://www.w3.org/2005/Atom updated"`
+	Author    []struct {
+		Name string `xml:"http://www.w3.org/2005/Atom name"`
+	} `xml:"http://www.w3.org/2005/Atom author"`
+	Category []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://www.w3.org/2005/Atom category"`
+
+	MediaThumbnail []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaContent   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup     []MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+func (f AtomFeed) toRSS() RSSFeed {
+	var feed RSSFeed
+	feed.Channel.Title = f.Title
+	feed.Channel.Link = alternateLink(f.Link)
+	feed.Channel.Description = f.Subtitle
+	for _, entry := range f.Entry {
+		item := RSSItem{
+			Title:          entry.Title,
+			Link:           alternateLink(entry.Link),
+			Description:    entry.Summary.String(),
+			Content:        entry.Content.String(),
+			MediaThumbnail: entry.MediaThumbnail,
+			MediaContent:   entry.MediaContent,
+			MediaGroup:     entry.MediaGroup,
+		}
+		for _, group := range entry.MediaGroup {
+			if item.Description == "" {
+				item.Description = group.Description
+			}
+		}
+		item.PubDate = entry.Published
+		if item.PubDate == "" {
+			item.PubDate = entry.Updated
+		}
+		for _, author := range entry.Author {
+			item.Creator = append(item.Creator, author.Name)
+		}
+		for _, category := range entry.Category {
+			item.Category = append(item.Category, category.Term)
+		}
+		for _, link := range entry.Link {
+			if link.Rel == "enclosure" {
+				item.Enclosure = append(item.Enclosure, RSSEnclosure{URL: link.Href, Type: link.Type})
+			}
+		}
+		feed.Channel.Item = append(feed.Channel.Item, item)
+	}
+	return feed
+}
+
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// isAtom reports whether the document's root element is an Atom feed.
+func isAtom(data []byte) bool {
+	d := newDecoder(data)
+	d.Strict = false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local == "feed"
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package rss
+
+import "net/http"
+
+// Auth holds the credentials sent with requests for a private feed. At most
+// one of basic auth and a bearer token is used; Headers are added as is.
+type Auth struct {
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Token    string            `json:"token,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+func (a *Auth) apply(req *http.Request) {
+	if a == nil {
+		return
+	}
+	for name, value := range a.Headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case a.Token != "":
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	case a.Username != "" || a.Password != "":
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+}
+
+type authKey struct{}
+
+// stripOnRedirect removes the custom headers of a request's Auth once it is
+// redirected to another host. net/http already drops Authorization.
+func stripOnRedirect(req *http.Request, via []*http.Request) {
+	auth, ok := req.Context().Value(authKey{}).(*Auth)
+	if !ok || req.URL.Host == via[0].URL.Host {
+		return
+	}
+	for name := range auth.Headers {
+		req.Header.Del(name)
+	}
+}
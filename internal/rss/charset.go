@@ -0,0 +1,82 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+var xmlEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*encoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// toUTF8 transcodes a feed to UTF-8. The charset from the Content-Type header
+// wins over the XML declaration, as the XML media type spec requires, except
+// when the header claims UTF-8 for a body that isn't valid UTF-8.
+func toUTF8(data []byte, contentType string) ([]byte, error) {
+	label := headerCharset(contentType)
+	if label == "" || (isUTF8Label(label) && !utf8.Valid(data)) {
+		label = declaredCharset(data)
+	}
+	if label == "" || isUTF8Label(label) {
+		return data, nil
+	}
+
+	enc, name := charset.Lookup(label)
+	if enc == nil {
+		return nil, fmt.Errorf("unsupported charset %v", label)
+	}
+	r := enc.NewDecoder().Reader(bytes.NewReader(data))
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %v: %w", name, err)
+	}
+	return bytes.TrimPrefix(out, []byte("\xef\xbb\xbf")), nil
+}
+
+func headerCharset(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(params["charset"], `"' `)
+}
+
+func declaredCharset(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xef\xbb\xbf")):
+		return "utf-8"
+	case bytes.HasPrefix(data, []byte("\xff\xfe")):
+		return "utf-16le"
+	case bytes.HasPrefix(data, []byte("\xfe\xff")):
+		return "utf-16be"
+	}
+	m := xmlEncoding.FindSubmatch(data[:min(len(data), 1024)])
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
+
+func isUTF8Label(label string) bool {
+	label = strings.ToLower(label)
+	return label == "utf-8" || label == "utf8" || label == "us-ascii" || label == "ascii"
+}
+
+// newDecoder returns a decoder for a document already transcoded by toUTF8,
+// so the encoding in its XML declaration is no longer accurate and ignored.
+func newDecoder(data []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return d
+}
@@ -0,0 +1,62 @@
+package rss
+
+import "testing"
+
+func TestToUTF8(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		contentType string
+		want        string
+	}{
+		{
+			name: "windows-1252 declared in the document",
+			data: "<?xml version=\"1.0\" encoding=\"windows-1252\"?><rss><channel><item><title>\x93Caf\xe9\x94 \x80 5</title></item></channel></rss>",
+			want: "“Café” € 5",
+		},
+		{
+			name:        "iso-8859-1 from the content type",
+			data:        "<?xml version=\"1.0\"?><rss><channel><item><title>Gr\xfc\xdfe aus K\xf6ln</title></item></channel></rss>",
+			contentType: "application/rss+xml; charset=ISO-8859-1",
+			want:        "Grüße aus Köln",
+		},
+		{
+			name:        "header charset wins over the declaration",
+			data:        "<?xml version=\"1.0\" encoding=\"utf-8\"?><rss><channel><item><title>na\xefve</title></item></channel></rss>",
+			contentType: "text/xml; charset=iso-8859-1",
+			want:        "naïve",
+		},
+		{
+			name:        "utf-8 with a byte order mark",
+			data:        "\xef\xbb\xbf<?xml version=\"1.0\"?><rss><channel><item><title>Zoë</title></item></channel></rss>",
+			contentType: "application/xml",
+			want:        "Zoë",
+		},
+		{
+			name: "utf-16 with a byte order mark",
+			data: "\xff\xfe<\x00r\x00s\x00s\x00>\x00<\x00c\x00h\x00a\x00n\x00n\x00e\x00l\x00>\x00<\x00i\x00t\x00e\x00m\x00>\x00<\x00t\x00i\x00t\x00l\x00e\x00>\x00\xe9\x00<\x00/\x00t\x00i\x00t\x00l\x00e\x00>\x00<\x00/\x00i\x00t\x00e\x00m\x00>\x00<\x00/\x00c\x00h\x00a\x00n\x00n\x00e\x00l\x00>\x00<\x00/\x00r\x00s\x00s\x00>\x00",
+			want: "é",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := toUTF8([]byte(tt.data), tt.contentType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			feed, err := parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(feed.Channel.Item) != 1 || feed.Channel.Item[0].Title != tt.want {
+				t.Errorf("items %+v, want one titled %q", feed.Channel.Item, tt.want)
+			}
+		})
+	}
+}
+
+func TestToUTF8UnknownCharset(t *testing.T) {
+	if _, err := toUTF8([]byte("<rss/>"), "text/xml; charset=x-klingon"); err == nil {
+		t.Error("expected an error for an unknown charset")
+	}
+}
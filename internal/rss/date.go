@@ -0,0 +1,66 @@
+package rss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the formats seen in the wild for RSS pubDate and Atom
+// published values. RFC 822 allows the weekday and seconds to be left out,
+// and plenty of feeds use single digit days or named zones.
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"02 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	"02 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// rfc822Zones are the zone names RFC 822 defines. time.Parse only knows the
+// offset of a zone name when the local zone uses it and takes every other one
+// to be UTC, so these are swapped for numeric offsets before parsing.
+var rfc822Zones = map[string]string{
+	"UT":  "+0000",
+	"UTC": "+0000",
+	"GMT": "+0000",
+	"Z":   "+0000",
+	"EST": "-0500",
+	"EDT": "-0400",
+	"CST": "-0600",
+	"CDT": "-0500",
+	"MST": "-0700",
+	"MDT": "-0600",
+	"PST": "-0800",
+	"PDT": "-0700",
+}
+
+// ParseDate parses a feed date in any of the common RSS and Atom formats and
+// returns it in UTC. Dates without a zone are taken to be in UTC.
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if i := strings.LastIndexByte(value, ' '); i >= 0 {
+		if offset, ok := rfc822Zones[strings.ToUpper(value[i+1:])]; ok {
+			value = value[:i+1] + offset
+		}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
@@ -0,0 +1,75 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Mon, 05 Jan 2026 09:30:00 PST", "2026-01-05T17:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 PDT", "2026-01-05T16:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 MST", "2026-01-05T16:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 MDT", "2026-01-05T15:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 CST", "2026-01-05T15:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 CDT", "2026-01-05T14:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 EST", "2026-01-05T14:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 EDT", "2026-01-05T13:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 GMT", "2026-01-05T09:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 UT", "2026-01-05T09:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 Z", "2026-01-05T09:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 UTC", "2026-01-05T09:30:00Z"},
+		{"Mon, 5 Jan 2026 09:30:00 est", "2026-01-05T14:30:00Z"},
+		{"5 Jan 2026 09:30:00 PST", "2026-01-05T17:30:00Z"},
+		{"05 Jan 26 09:30 EDT", "2026-01-05T13:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30 -0800", "2026-01-05T17:30:00Z"},
+		{"Mon, 05 Jan 2026 09:30:00 +0100", "2026-01-05T08:30:00Z"},
+		{"2026-01-05T09:30:00-08:00", "2026-01-05T17:30:00Z"},
+		{"2026-01-05T09:30:00.123Z", "2026-01-05T09:30:00.123Z"},
+		{"2026-01-05T09:30:00", "2026-01-05T09:30:00Z"},
+		{"2026-01-05", "2026-01-05T00:00:00Z"},
+		{"  Mon, 05 Jan 2026 09:30:00 GMT\n", "2026-01-05T09:30:00Z"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.value)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %v", tt.value, err)
+			continue
+		}
+		if got.Format(time.RFC3339Nano) != tt.want || got.Location() != time.UTC {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.value, got.Format(time.RFC3339Nano), tt.want)
+		}
+	}
+}
+
+func TestParseDateInOtherLocalZones(t *testing.T) {
+	// time.Parse resolves zone names against the local zone, so the result
+	// must not depend on where gator runs.
+	local := time.Local
+	defer func() { time.Local = local }()
+	for _, name := range []string{"UTC", "America/Los_Angeles", "Asia/Tokyo"} {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Local = loc
+		got, err := ParseDate("Mon, 05 Jan 2026 09:30:00 PST")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := time.Date(2026, 1, 5, 17, 30, 0, 0, time.UTC); !got.Equal(want) {
+			t.Errorf("with local zone %v got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseDateRejects(t *testing.T) {
+	for _, value := range []string{"", "yesterday", "Mon, 05 Jan 2026"} {
+		if _, err := ParseDate(value); err == nil {
+			t.Errorf("ParseDate(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package rss
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+const acceptEncoding = "gzip, deflate, br"
+
+// FetchStats records what a feed request cost. BytesTransferred is the size
+// of the body on the wire and BytesDecoded its size after decompression.
+// Duration covers the request until the body has been read.
+type FetchStats struct {
+	StatusCode       int
+	BytesTransferred int64
+	BytesDecoded     int64
+	Duration         time.Duration
+}
+
+// decodeBody undoes the Content-Encoding of a response body. Several
+// encodings are listed in the order they were applied.
+func decodeBody(body io.Reader, contentEncoding string) (io.Reader, error) {
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch strings.ToLower(strings.TrimSpace(encodings[i])) {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			body, err = gzip.NewReader(body)
+		case "deflate":
+			body, err = newDeflateReader(body)
+		case "br":
+			body = brotli.NewReader(body)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %v", encodings[i])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %v response: %w", encodings[i], err)
+		}
+	}
+	return body, nil
+}
+
+// newDeflateReader reads "deflate" bodies, which should be zlib wrapped but
+// are raw deflate streams from some servers.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// A zlib header is a CMF byte for deflate and a check value making the
+	// pair a multiple of 31.
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,187 @@
+package rss
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	DefaultTimeout         = 30 * time.Second
+	DefaultConnectTimeout  = 10 * time.Second
+	DefaultMaxBodySize     = 10 << 20
+	DefaultMaxRedirects    = 10
+	DefaultHostRate        = 1
+	DefaultHostBurst       = 5
+	DefaultHostConcurrency = 2
+)
+
+type FetcherOptions struct {
+	// Timeout bounds a whole request, including reading the body.
+	Timeout        time.Duration
+	ConnectTimeout time.Duration
+	MaxBodySize    int64
+	// UserAgent replaces the default "gator" product name.
+	UserAgent string
+	// ContactURL is appended to the User-Agent so feed owners can reach us.
+	ContactURL string
+	// ProxyURL overrides the proxy from the environment.
+	ProxyURL string
+	// CABundle is a PEM file of certificates trusted in addition to the
+	// system roots.
+	CABundle string
+	// MaxRedirects of zero uses the default, a negative value disables
+	// following redirects.
+	MaxRedirects int
+	// HostRate is the number of requests per second sent to one host, with
+	// up to HostBurst sent at once after a quiet spell. A negative HostRate
+	// disables the limit.
+	HostRate  float64
+	HostBurst int
+	// HostConcurrency caps the requests in flight to one host. A negative
+	// value disables the cap.
+	HostConcurrency int
+}
+
+// Fetcher downloads feeds with a configured http.Client. Client is exported
+// so other requests made on behalf of a feed, like full article downloads,
+// are subject to the same settings.
+type Fetcher struct {
+	Client      *http.Client
+	MaxBodySize int64
+}
+
+func NewFetcher(opts FetcherOptions) (*Fetcher, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.ConnectTimeout == 0 {
+		opts.ConnectTimeout = DefaultConnectTimeout
+	}
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+	if opts.HostRate == 0 {
+		opts.HostRate = DefaultHostRate
+	}
+	if opts.HostBurst == 0 {
+		opts.HostBurst = DefaultHostBurst
+	}
+	if opts.HostConcurrency == 0 {
+		opts.HostConcurrency = DefaultHostConcurrency
+	}
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = "gator"
+	}
+	if opts.ContactURL != "" {
+		userAgent = fmt.Sprintf("%v (+%v)", userAgent, opts.ContactURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	if opts.ProxyURL != "" {
+		proxy, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if opts.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %v", opts.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	limiter := newHostLimiter(opts.HostRate, opts.HostBurst, opts.HostConcurrency)
+	maxRedirects := opts.MaxRedirects
+	return &Fetcher{
+		Client: &http.Client{
+			Transport: &userAgentTransport{
+				userAgent: userAgent,
+				base:      &limitTransport{limiter: limiter, base: transport},
+			},
+			Timeout: opts.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if maxRedirects < 0 {
+					return http.ErrUseLastResponse
+				}
+				if len(via) > maxRedirects {
+					return fmt.Errorf("stopped after %v redirects", maxRedirects)
+				}
+				if chain, ok := req.Context().Value(redirectsKey{}).(*redirectChain); ok {
+					chain.record(req.Response.StatusCode, req.URL.String())
+				}
+				stripOnRedirect(req, via)
+				return nil
+			},
+		},
+		MaxBodySize: opts.MaxBodySize,
+	}, nil
+}
+
+type userAgentTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
+
+type redirectsKey struct{}
+
+// redirectChain tracks where a request has permanently moved to. Only an
+// unbroken run of permanent redirects from the original URL counts; after a
+// temporary one, later hops may change again.
+type redirectChain struct {
+	permanent string
+	broken    bool
+}
+
+func (c *redirectChain) record(status int, to string) {
+	if c.broken {
+		return
+	}
+	if status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect {
+		c.permanent = to
+		return
+	}
+	c.broken = true
+}
+
+// StatusError is returned for responses other than 200 OK. RetryAfter is
+// set for 429 Too Many Requests.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("feed request failed: %v", e.Status)
+}
@@ -0,0 +1,30 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetcherWithRedirectsDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old.xml" {
+			http.Redirect(w, r, "/new.xml", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte(`<rss><channel><item><title>moved</title></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	f, err := NewFetcher(FetcherOptions{MaxRedirects: -1, HostRate: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.FetchFeed(context.Background(), server.URL+"/old.xml", nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("fetching a redirected feed returned %v, want the 301 itself", err)
+	}
+}
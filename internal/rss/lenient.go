@@ -0,0 +1,142 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// parse decodes a UTF-8 feed document. Well-formed documents are decoded
+// strictly. Anything else is decoded again leniently: HTML entities are
+// accepted, stray ampersands and unclosed HTML elements are tolerated, and
+// the items read before an unrecoverable error are kept. Whatever had to be
+// worked around is recorded in the feed's Warnings.
+func parse(data []byte) (*RSSFeed, error) {
+	var warnings []string
+	if bytes.HasPrefix(data, []byte("\xef\xbb\xbf")) {
+		data = data[3:]
+		warnings = append(warnings, "stripped byte order mark")
+	}
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) != len(data) {
+		data = trimmed
+		warnings = append(warnings, "stripped whitespace before document start")
+	}
+
+	feed, err := decodeStrict(data)
+	if err == nil {
+		feed.Warnings = warnings
+		return feed, nil
+	}
+	warnings = append(warnings, fmt.Sprintf("not well-formed, parsed leniently: %v", err))
+
+	feed, err = decodeLenient(data)
+	if err != nil {
+		if len(feed.Channel.Item) == 0 {
+			return &RSSFeed{}, fmt.Errorf("failed to unmarshal response data: %w", err)
+		}
+		warnings = append(warnings, fmt.Sprintf("kept %v items read before error: %v", len(feed.Channel.Item), err))
+	}
+	feed.Warnings = warnings
+	return feed, nil
+}
+
+func decodeStrict(data []byte) (*RSSFeed, error) {
+	var feed RSSFeed
+	if isAtom(data) {
+		var atom AtomFeed
+		if err := newDecoder(data).Decode(&atom); err != nil {
+			return &RSSFeed{}, err
+		}
+		feed = atom.toRSS()
+	} else if err := newDecoder(data).Decode(&feed); err != nil {
+		return &RSSFeed{}, err
+	}
+	return &feed, nil
+}
+
+// decodeLenient walks the document token by token, decoding each item on its
+// own so that one broken item, or a truncated document, only loses what comes
+// after it.
+func decodeLenient(data []byte) (*RSSFeed, error) {
+	d := newDecoder(data)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	var feed RSSFeed
+	var atom AtomFeed
+	isAtomFeed := false
+	var path []string
+	finish := func() *RSSFeed {
+		if isAtomFeed {
+			converted := atom.toRSS()
+			return &converted
+		}
+		return &feed
+	}
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return finish(), nil
+		}
+		if err != nil {
+			return finish(), err
+		}
+
+		switch tok := tok.(type) {
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+			continue
+		case xml.StartElement:
+			if len(path) == 0 && tok.Name.Local == "feed" {
+				isAtomFeed = true
+			}
+			var target any
+			switch {
+			case isAtomFeed && len(path) == 1:
+				switch tok.Name.Local {
+				case "entry":
+					atom.Entry = append(atom.Entry, AtomEntry{})
+					target = &atom.Entry[len(atom.Entry)-1]
+				case "title":
+					target = &atom.Title
+				case "subtitle":
+					target = &atom.Subtitle
+				case "link":
+					atom.Link = append(atom.Link, AtomLink{})
+					target = &atom.Link[len(atom.Link)-1]
+				}
+			case !isAtomFeed && len(path) == 2 && path[1] == "channel":
+				switch tok.Name.Local {
+				case "item":
+					feed.Channel.Item = append(feed.Channel.Item, RSSItem{})
+					target = &feed.Channel.Item[len(feed.Channel.Item)-1]
+				case "title":
+					target = &feed.Channel.Title
+				case "link":
+					target = &feed.Channel.Link
+				case "description":
+					target = &feed.Channel.Description
+				}
+			}
+			if target == nil {
+				path = append(path, tok.Name.Local)
+				continue
+			}
+			if err := d.DecodeElement(target, &tok); err != nil {
+				// Drop the element that failed part way through.
+				switch {
+				case isAtomFeed && tok.Name.Local == "entry":
+					atom.Entry = atom.Entry[:len(atom.Entry)-1]
+				case !isAtomFeed && tok.Name.Local == "item":
+					feed.Channel.Item = feed.Channel.Item[:len(feed.Channel.Item)-1]
+				}
+				return finish(), err
+			}
+		}
+	}
+}
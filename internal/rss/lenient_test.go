@@ -0,0 +1,85 @@
+package rss
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		titles   []string
+		warnings []string
+	}{
+		{
+			name:     "bare ampersand",
+			data:     `<rss><channel><title>Tom & Jerry</title><item><title>Salt & pepper</title></item></channel></rss>`,
+			titles:   []string{"Salt & pepper"},
+			warnings: []string{"not well-formed"},
+		},
+		{
+			name:     "undeclared html entities",
+			data:     `<rss><channel><item><title>Caf&eacute; &mdash; &nbsp;menu</title></item></channel></rss>`,
+			titles:   []string{"Café —  menu"},
+			warnings: []string{"not well-formed"},
+		},
+		{
+			name:     "truncated document keeps earlier items",
+			data:     `<rss><channel><item><title>one</title></item><item><title>two</title></item><item><title>thr`,
+			titles:   []string{"one", "two"},
+			warnings: []string{"not well-formed", "kept 2 items read before error"},
+		},
+		{
+			name:     "byte order mark and leading whitespace",
+			data:     "\xef\xbb\xbf\n  <rss><channel><item><title>ok</title></item></channel></rss>",
+			titles:   []string{"ok"},
+			warnings: []string{"stripped byte order mark", "stripped whitespace before document start"},
+		},
+		{
+			name:     "atom with a bare ampersand",
+			data:     `<feed xmlns="http://www.w3.org/2005/Atom"><title>A & B</title><entry><title>R&D</title></entry></feed>`,
+			titles:   []string{"R&D"},
+			warnings: []string{"not well-formed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := parse([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, item := range feed.Channel.Item {
+				titles = append(titles, item.Title)
+			}
+			if strings.Join(titles, "|") != strings.Join(tt.titles, "|") {
+				t.Errorf("titles %q, want %q", titles, tt.titles)
+			}
+			if len(feed.Warnings) != len(tt.warnings) {
+				t.Fatalf("warnings %q, want %v of them", feed.Warnings, len(tt.warnings))
+			}
+			for i, want := range tt.warnings {
+				if !strings.HasPrefix(feed.Warnings[i], want) {
+					t.Errorf("warning %v is %q, want it to start with %q", i, feed.Warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseWellFormedHasNoWarnings(t *testing.T) {
+	feed, err := parse([]byte(`<rss><channel><item><title>Tom &amp; Jerry</title></item></channel></rss>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Warnings) != 0 || feed.Channel.Item[0].Title != "Tom & Jerry" {
+		t.Errorf("got %q with warnings %q", feed.Channel.Item[0].Title, feed.Warnings)
+	}
+}
+
+func TestParseGarbage(t *testing.T) {
+	if _, err := parse([]byte("<html><body>not a feed")); err == nil {
+		t.Error("expected an error for a document with no items")
+	}
+}
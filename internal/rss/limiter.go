@@ -0,0 +1,165 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultRetryAfter = time.Minute
+
+// RateLimitError is returned without making a request while a host is backing
+// off after answering 429 Too Many Requests.
+type RateLimitError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v is rate limiting us until %v", e.Host, e.Until.Format(time.RFC1123))
+}
+
+// hostLimiter gives each host a token bucket refilled at rate per second with
+// room for burst requests, and caps the requests in flight to it.
+type hostLimiter struct {
+	rate        float64
+	burst       float64
+	concurrency int
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	tokens       float64
+	last         time.Time
+	slots        chan struct{}
+	blockedUntil time.Time
+}
+
+func newHostLimiter(rate float64, burst, concurrency int) *hostLimiter {
+	return &hostLimiter{
+		rate:        rate,
+		burst:       float64(max(burst, 1)),
+		concurrency: concurrency,
+		hosts:       map[string]*hostState{},
+	}
+}
+
+func (l *hostLimiter) host(name string) *hostState {
+	h, ok := l.hosts[name]
+	if !ok {
+		h = &hostState{tokens: l.burst, last: time.Now()}
+		if l.concurrency > 0 {
+			h.slots = make(chan struct{}, l.concurrency)
+		}
+		l.hosts[name] = h
+	}
+	return h
+}
+
+// acquire waits for a token and a free slot for host, and returns a func to
+// give the slot back.
+func (l *hostLimiter) acquire(ctx context.Context, name string) (func(), error) {
+	l.mu.Lock()
+	h := l.host(name)
+	now := time.Now()
+	if now.Before(h.blockedUntil) {
+		l.mu.Unlock()
+		return nil, &RateLimitError{Host: name, Until: h.blockedUntil}
+	}
+	var wait time.Duration
+	if l.rate > 0 {
+		h.tokens = min(l.burst, h.tokens+now.Sub(h.last).Seconds()*l.rate)
+		h.last = now
+		h.tokens--
+		if h.tokens < 0 {
+			wait = time.Duration(-h.tokens / l.rate * float64(time.Second))
+		}
+	}
+	slots := h.slots
+	l.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case slots <- struct{}{}:
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-slots }) }, nil
+}
+
+func (l *hostLimiter) backoff(name string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h := l.host(name)
+	if until.After(h.blockedUntil) {
+		h.blockedUntil = until
+	}
+}
+
+type limitTransport struct {
+	limiter *hostLimiter
+	base    http.RoundTripper
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	release, err := t.limiter.acquire(req.Context(), host)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		t.limiter.backoff(host, time.Now().Add(retryAfter(res.Header.Get("Retry-After"))))
+	}
+	// Hold the slot until the body has been read.
+	res.Body = &releaseBody{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds
+// or an HTTP date.
+func retryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
@@ -0,0 +1,125 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHostLimiterSpacesRequestsPerHost(t *testing.T) {
+	l := newHostLimiter(20, 1, 0)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		release, err := l.acquire(ctx, "a.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	// The first request spends the burst, the next two wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("three requests to one host took %v, want about 100ms", elapsed)
+	}
+
+	start = time.Now()
+	if _, err := l.acquire(ctx, "b.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("a request to another host waited %v", elapsed)
+	}
+}
+
+func TestHostLimiterConcurrency(t *testing.T) {
+	l := newHostLimiter(0, 1, 1)
+	release, err := l.acquire(context.Background(), "a.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "a.example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second request while the slot was taken returned %v", err)
+	}
+	release()
+	if _, err := l.acquire(context.Background(), "a.example.com"); err != nil {
+		t.Errorf("request after release returned %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLimitTransportBacksOffAfter429(t *testing.T) {
+	calls := 0
+	transport := &limitTransport{
+		limiter: newHostLimiter(0, 1, 0),
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+			if req.URL.Host == "busy.example.com" {
+				res.StatusCode = http.StatusTooManyRequests
+				res.Header.Set("Retry-After", "120")
+			}
+			return res, nil
+		}),
+	}
+	get := func(url string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return transport.RoundTrip(req)
+	}
+
+	res, err := get("https://busy.example.com/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	_, err = get("https://busy.example.com/feed")
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("request during back off returned %v, want a RateLimitError", err)
+	}
+	if wait := time.Until(rateErr.Until); wait < 110*time.Second || wait > 120*time.Second {
+		t.Errorf("backing off for %v, want 120s", wait)
+	}
+	if calls != 1 {
+		t.Errorf("%v requests reached the server, want 1", calls)
+	}
+
+	if _, err := get("https://quiet.example.com/feed"); err != nil {
+		t.Errorf("another host was blocked: %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"30", 30 * time.Second, 30 * time.Second},
+		{"0", 0, 0},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 59 * time.Minute, time.Hour},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, 0},
+		{"", defaultRetryAfter, defaultRetryAfter},
+		{"soon", defaultRetryAfter, defaultRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("retryAfter(%q) = %v, want between %v and %v", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package rss
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type MediaThumbnail struct {
+	URL    string `xml:"url,attr"`
+	Width  string `xml:"width,attr"`
+	Height string `xml:"height,attr"`
+}
+
+type MediaContent struct {
+	URL       string           `xml:"url,attr"`
+	Type      string           `xml:"type,attr"`
+	Medium    string           `xml:"medium,attr"`
+	Width     string           `xml:"width,attr"`
+	Height    string           `xml:"height,attr"`
+	Thumbnail []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type MediaGroup struct {
+	Content     []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnail   []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Description string           `xml:"http://search.yahoo.com/mrss/ description"`
+}
+
+// Thumbnail picks a representative image for the item, preferring Media RSS
+// thumbnails, then image media, the itunes image, image enclosures and
+// finally the first image in the item's content. Relative URLs are resolved
+// against the item link. It returns an empty string if there is no image.
+func (item RSSItem) Thumbnail() string {
+	thumbnails := item.MediaThumbnail
+	contents := item.MediaContent
+	for _, group := range item.MediaGroup {
+		thumbnails = append(thumbnails, group.Thumbnail...)
+		contents = append(contents, group.Content...)
+	}
+	for _, content := range contents {
+		thumbnails = append(thumbnails, content.Thumbnail...)
+	}
+
+	best, bestArea := "", -1
+	for _, thumbnail := range thumbnails {
+		if thumbnail.URL == "" {
+			continue
+		}
+		if area := size(thumbnail.Width) * size(thumbnail.Height); area > bestArea {
+			best, bestArea = thumbnail.URL, area
+		}
+	}
+	if best == "" {
+		for _, content := range contents {
+			if content.URL != "" && (content.Medium == "image" || strings.HasPrefix(content.Type, "image/")) {
+				best = content.URL
+				break
+			}
+		}
+	}
+	if best == "" {
+		best = item.Image.Href
+	}
+	if best == "" {
+		for _, enclosure := range item.Enclosure {
+			if strings.HasPrefix(enclosure.Type, "image/") {
+				best = enclosure.URL
+				break
+			}
+		}
+	}
+	if best == "" {
+		best = FirstImage(item.Content)
+	}
+	if best == "" {
+		best = FirstImage(item.Description)
+	}
+	return resolve(item.Link, best)
+}
+
+// FirstImage returns the src of the first img in an HTML fragment that isn't
+// a tracking pixel.
+func FirstImage(src string) string {
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if tok.DataAtom != atom.Img {
+				continue
+			}
+			n := &html.Node{Type: html.ElementNode, Data: tok.Data, DataAtom: tok.DataAtom, Attr: tok.Attr}
+			if src := getAttr(n, "src"); src != "" && !isTrackingPixel(n) {
+				return src
+			}
+		}
+	}
+}
+
+func size(s string) int {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(s), "px"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func resolve(base, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil || !b.IsAbs() {
+		return ref
+	}
+	u, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
@@ -2,42 +2,95 @@ package rss
 
 import (
 	"context"
-	"encoding/xml"
 	"fmt"
 	"html"
 	"io"
 	"net/http"
+	"time"
 )
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+// FetchFeed downloads and parses the feed at feedURL. The returned feed
+// records the URL it was finally fetched from, and the URL it permanently
+// moved to if it was redirected with a 301 or 308. Responses other than
+// 200 OK are returned as a *StatusError. auth may be nil for public feeds.
+// The feed's Stats are filled in even when an error is returned.
+func (f *Fetcher) FetchFeed(ctx context.Context, feedURL string, auth *Auth) (*RSSFeed, error) {
+	start := time.Now()
+	stats := FetchStats{}
+	fail := func(err error) (*RSSFeed, error) {
+		stats.Duration = time.Since(start)
+		return &RSSFeed{Stats: stats}, err
+	}
+
+	chain := &redirectChain{}
+	ctx = context.WithValue(ctx, redirectsKey{}, chain)
+	if auth != nil {
+		ctx = context.WithValue(ctx, authKey{}, auth)
+	}
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 	if err != nil {
-		return &RSSFeed{}, fmt.Errorf("failed to create feed request: %w", err)
+		return fail(fmt.Errorf("failed to create feed request: %w", err))
 	}
-	req.Header.Set("User-Agent", "gator")
+	auth.apply(req)
+	// Asking for an encoding ourselves turns off the transport's transparent
+	// gzip, so the compressed size can be counted.
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 
-	client := http.DefaultClient
-	res, err := client.Do(req)
+	res, err := f.Client.Do(req)
 	if err != nil {
-		return &RSSFeed{}, fmt.Errorf("feed request failed: %w", err)
+		return fail(fmt.Errorf("feed request failed: %w", err))
 	}
 	defer res.Body.Close()
+	stats.StatusCode = res.StatusCode
+	if res.StatusCode != http.StatusOK {
+		statusErr := &StatusError{
+			URL:        res.Request.URL.String(),
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+		}
+		if res.StatusCode == http.StatusTooManyRequests {
+			statusErr.RetryAfter = retryAfter(res.Header.Get("Retry-After"))
+		}
+		return fail(statusErr)
+	}
 
-	var feed RSSFeed
-	data, err := io.ReadAll(res.Body)
+	counter := &countingReader{r: res.Body}
+	body, err := decodeBody(counter, res.Header.Get("Content-Encoding"))
+	if err != nil {
+		return fail(err)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, f.MaxBodySize+1))
+	stats.BytesTransferred = counter.n
+	stats.BytesDecoded = int64(len(data))
 	if err != nil {
-		return &RSSFeed{}, fmt.Errorf("failed to read response: %w", err)
+		return fail(fmt.Errorf("failed to read response: %w", err))
+	}
+	if int64(len(data)) > f.MaxBodySize {
+		return fail(fmt.Errorf("response larger than %v bytes", f.MaxBodySize))
 	}
-	if err := xml.Unmarshal(data, &feed); err != nil {
-		return &RSSFeed{}, fmt.Errorf("failed to unmarshal response data: %w", err)
+	stats.Duration = time.Since(start)
+	data, err = toUTF8(data, res.Header.Get("Content-Type"))
+	if err != nil {
+		return fail(err)
+	}
+	feed, err := parse(data)
+	if err != nil {
+		return fail(err)
 	}
+	feed.URL = res.Request.URL.String()
+	feed.StatusCode = res.StatusCode
+	feed.PermanentURL = chain.permanent
+	feed.Stats = stats
 
 	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
 	feed.Channel.Description = html.UnescapeString(feed.Channel.Description)
 	for i := range feed.Channel.Item {
 		feed.Channel.Item[i].Title = html.UnescapeString(feed.Channel.Item[i].Title)
 		feed.Channel.Item[i].Description = html.UnescapeString(feed.Channel.Item[i].Description)
+		for j := range feed.Channel.Item[i].Category {
+			feed.Channel.Item[i].Category[j] = html.UnescapeString(feed.Channel.Item[i].Category[j])
+		}
 	}
 
-	return &feed, nil
+	return feed, nil
 }
@@ -0,0 +1,264 @@
+package rss
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Policy is an allowlist of the elements, attributes and URL schemes that
+// survive sanitization. Elements that are not allowed are unwrapped, keeping
+// their text, except for those in Drop which are removed with their content.
+type Policy struct {
+	Elements            map[string][]string
+	GlobalAttributes    []string
+	URLSchemes          []string
+	Drop                []string
+	StripTrackingImages bool
+}
+
+var urlAttributes = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"cite":   true,
+	"poster": true,
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		Elements: map[string][]string{
+			"a":          {"href"},
+			"abbr":       nil,
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"caption":    nil,
+			"cite":       nil,
+			"code":       nil,
+			"dd":         nil,
+			"del":        nil,
+			"div":        nil,
+			"dl":         nil,
+			"dt":         nil,
+			"em":         nil,
+			"figcaption": nil,
+			"figure":     nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "width", "height"},
+			"ins":        nil,
+			"kbd":        nil,
+			"li":         nil,
+			"mark":       nil,
+			"ol":         {"start"},
+			"p":          nil,
+			"pre":        nil,
+			"q":          {"cite"},
+			"s":          nil,
+			"samp":       nil,
+			"small":      nil,
+			"span":       nil,
+			"strong":     nil,
+			"sub":        nil,
+			"sup":        nil,
+			"table":      nil,
+			"tbody":      nil,
+			"td":         {"colspan", "rowspan"},
+			"tfoot":      nil,
+			"th":         {"colspan", "rowspan"},
+			"thead":      nil,
+			"tr":         nil,
+			"u":          nil,
+			"ul":         nil,
+		},
+		GlobalAttributes:    []string{"title", "lang", "dir"},
+		URLSchemes:          []string{"http", "https", "mailto"},
+		Drop:                []string{"script", "style", "iframe", "frame", "frameset", "object", "embed", "applet", "form", "noscript", "template", "svg", "math", "head", "title", "link", "meta", "base"},
+		StripTrackingImages: true,
+	}
+}
+
+// Allow adds an element, and attributes for it, to the policy.
+func (p *Policy) Allow(element string, attrs ...string) {
+	if p.Elements == nil {
+		p.Elements = map[string][]string{}
+	}
+	element = strings.ToLower(element)
+	p.Elements[element] = append(p.Elements[element], attrs...)
+	for i := range p.Drop {
+		if p.Drop[i] == element {
+			p.Drop = append(p.Drop[:i], p.Drop[i+1:]...)
+			break
+		}
+	}
+}
+
+// Sanitize returns src with everything not allowed by the policy removed.
+// Relative URLs are resolved against baseURL, usually the item link.
+func (p Policy) Sanitize(src, baseURL string) string {
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(src), body)
+	if err != nil {
+		return html.EscapeString(src)
+	}
+
+	out := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		p.sanitize(n, out, base)
+	}
+	var b strings.Builder
+	for c := out.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return ""
+		}
+	}
+	return b.String()
+}
+
+func (p Policy) sanitize(n, parent *html.Node, base *url.URL) {
+	switch n.Type {
+	case html.TextNode:
+		parent.AppendChild(&html.Node{Type: html.TextNode, Data: n.Data})
+		return
+	case html.ElementNode:
+	case html.DocumentNode:
+		p.sanitizeChildren(n, parent, base)
+		return
+	default:
+		return
+	}
+
+	name := strings.ToLower(n.Data)
+	for _, drop := range p.Drop {
+		if drop == name {
+			return
+		}
+	}
+	allowed, ok := p.Elements[name]
+	if !ok {
+		p.sanitizeChildren(n, parent, base)
+		return
+	}
+
+	clean := &html.Node{Type: html.ElementNode, Data: name, DataAtom: atom.Lookup([]byte(name))}
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if a.Namespace != "" || !p.allowsAttr(allowed, key) {
+			continue
+		}
+		val := a.Val
+		if urlAttributes[key] {
+			val = p.cleanURL(val, base)
+			if val == "" {
+				continue
+			}
+		}
+		clean.Attr = append(clean.Attr, html.Attribute{Key: key, Val: val})
+	}
+
+	switch clean.DataAtom {
+	case atom.Img:
+		if getAttr(clean, "src") == "" || (p.StripTrackingImages && isTrackingPixel(n)) {
+			return
+		}
+	case atom.A:
+		if getAttr(clean, "href") != "" {
+			clean.Attr = append(clean.Attr, html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"})
+		}
+	}
+	parent.AppendChild(clean)
+	p.sanitizeChildren(n, clean, base)
+}
+
+func (p Policy) sanitizeChildren(n, parent *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.sanitize(c, parent, base)
+	}
+}
+
+func (p Policy) allowsAttr(allowed []string, key string) bool {
+	if strings.HasPrefix(key, "on") {
+		return false
+	}
+	for _, a := range allowed {
+		if a == key {
+			return true
+		}
+	}
+	for _, a := range p.GlobalAttributes {
+		if a == key {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanURL resolves raw against baseURL and returns it if its scheme is
+// allowed by the policy, or "" if it isn't.
+func (p Policy) CleanURL(raw, baseURL string) string {
+	if strings.TrimSpace(raw) == "" {
+		return ""
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+	return p.cleanURL(raw, base)
+}
+
+func (p Policy) cleanURL(raw string, base *url.URL) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme == "" {
+		// A relative URL we could not resolve is harmless.
+		return u.String()
+	}
+	for _, scheme := range p.URLSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return u.String()
+		}
+	}
+	return ""
+}
+
+// isTrackingPixel reports whether an img is sized to be invisible, which is
+// how feeds embed view trackers.
+func isTrackingPixel(n *html.Node) bool {
+	width, werr := strconv.Atoi(strings.TrimSuffix(getAttr(n, "width"), "px"))
+	height, herr := strconv.Atoi(strings.TrimSuffix(getAttr(n, "height"), "px"))
+	if werr == nil && herr == nil && width <= 1 && height <= 1 {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(getAttr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") ||
+		strings.Contains(style, "visibility:hidden") ||
+		(strings.Contains(style, "width:1px") && strings.Contains(style, "height:1px")) ||
+		(strings.Contains(style, "width:0") && strings.Contains(style, "height:0"))
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package rss
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "event handlers",
+			src:  `<p onclick="alert(1)" ONMOUSEOVER="alert(2)" title="t">hi</p>`,
+			want: `<p title="t">hi</p>`,
+		},
+		{
+			name: "javascript url",
+			src:  `<a href="javascript:alert(1)">x</a>`,
+			want: `<a>x</a>`,
+		},
+		{
+			name: "mixed case javascript url",
+			src:  `<a href=" JaVaScRiPt:alert(1)">x</a>`,
+			want: `<a>x</a>`,
+		},
+		{
+			name: "entity encoded javascript url",
+			src:  `<a href="&#106;&#x61;vascript&colon;alert(1)">x</a>`,
+			want: `<a>x</a>`,
+		},
+		{
+			name: "javascript url with embedded tab",
+			src:  `<a href="java&#9;script:alert(1)">x</a>`,
+			want: `<a>x</a>`,
+		},
+		{
+			name: "data url image",
+			src:  `<img src="data:image/svg+xml;base64,PHN2Zz4=">`,
+			want: ``,
+		},
+		{
+			name: "dropped elements lose their content",
+			src:  `<p>a<script>alert(1)</script><iframe src="https://evil.example.com/">inner</iframe><style>p{}</style>b</p>`,
+			want: `<p>ab</p>`,
+		},
+		{
+			name: "unknown elements are unwrapped",
+			src:  `<section><font color="red">text</font></section>`,
+			want: `text`,
+		},
+		{
+			name: "relative urls resolve against the item link",
+			src:  `<a href="../other">x</a><img src="/img.png" alt="i">`,
+			want: `<a href="https://blog.example.com/other" rel="nofollow noopener noreferrer">x</a><img src="https://blog.example.com/img.png" alt="i"/>`,
+		},
+		{
+			name: "tracking pixel by size",
+			src:  `<p>a<img src="https://t.example.com/p.gif" width="1" height="1">b</p>`,
+			want: `<p>ab</p>`,
+		},
+		{
+			name: "tracking pixel by style",
+			src:  `<img src="https://t.example.com/p.gif" style="display: none">`,
+			want: ``,
+		},
+		{
+			name: "ordinary image",
+			src:  `<img src="https://cdn.example.com/photo.jpg" width="640" height="480">`,
+			want: `<img src="https://cdn.example.com/photo.jpg" width="640" height="480"/>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultPolicy().Sanitize(tt.src, "https://blog.example.com/posts/1")
+			if got != tt.want {
+				t.Errorf("Sanitize(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyAllow(t *testing.T) {
+	p := DefaultPolicy()
+	p.Allow("iframe", "src")
+	got := p.Sanitize(`<iframe src="https://video.example.com/embed" onload="x()"></iframe>`, "")
+	if want := `<iframe src="https://video.example.com/embed"></iframe>`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCleanURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://cdn.example.com/a.jpg", "https://cdn.example.com/a.jpg"},
+		{"/images/a.jpg", "https://blog.example.com/images/a.jpg"},
+		{"javascript:alert(1)", ""},
+		{"data:image/png;base64,iVBORw0KGgo=", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := DefaultPolicy().CleanURL(tt.raw, "https://blog.example.com/posts/1"); got != tt.want {
+			t.Errorf("CleanURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
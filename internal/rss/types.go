@@ -1,5 +1,7 @@
 package rss
 
+import "strings"
+
 type RSSFeed struct {
 	Channel struct {
 		Title       string    `xml:"title"`
@@ -7,11 +9,83 @@ type RSSFeed struct {
 		Description string    `xml:"description"`
 		Item        []RSSItem `xml:"item"`
 	} `xml:"channel"`
+	// Warnings describes problems worked around while parsing the feed.
+	Warnings []string `xml:"-"`
+	// URL is where the feed was fetched from after following redirects, and
+	// PermanentURL is set if the feed has permanently moved.
+	URL          string `xml:"-"`
+	PermanentURL string `xml:"-"`
+	StatusCode   int    `xml:"-"`
+	// Stats describes the request the feed was fetched with.
+	Stats FetchStats `xml:"-"`
 }
 
 type RSSItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Creator     []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Author      string   `xml:"author"`
+	Category    []string `xml:"category"`
+	Comments    string   `xml:"comments"`
+
+	Enclosure []RSSEnclosure `xml:"enclosure"`
+	Duration  string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Episode   string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	Season    string         `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	Image     struct {
+		Href string `xml:"href,attr"`
+	} `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+
+	MediaThumbnail []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaContent   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup     []MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+// RSSEnclosure is kept as strings since feeds often leave length empty or
+// fill it with something other than a number.
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// Authors combines dc:creator and author. RSS author values are usually an
+// email address with the name in parentheses, in which case only the name is
+// kept.
+func (item RSSItem) Authors() []string {
+	authors := []string{}
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		for _, a := range authors {
+			if strings.EqualFold(a, name) {
+				return
+			}
+		}
+		authors = append(authors, name)
+	}
+	for _, creator := range item.Creator {
+		add(creator)
+	}
+	author := item.Author
+	if open, close := strings.Index(author, "("), strings.LastIndex(author, ")"); open >= 0 && close > open {
+		author = author[open+1 : close]
+	}
+	add(author)
+	return authors
+}
+
+func (item RSSItem) Categories() []string {
+	categories := []string{}
+	for _, category := range item.Category {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return categories
 }
@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSealOpen(t *testing.T) {
+	key := newKey(t)
+	plaintext := []byte("Bearer s3cret")
+	sealed, err := Seal(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Error("sealed value contains the plaintext")
+	}
+	again, err := Seal(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sealed, again) {
+		t.Error("sealing twice gave the same value, the nonce is not random")
+	}
+
+	opened, err := Open(key, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Open returned %q, want %q", opened, plaintext)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	key := newKey(t)
+	sealed, err := Seal(key, []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name   string
+		key    []byte
+		sealed []byte
+	}{
+		{"wrong key", newKey(t), sealed},
+		{"tampered ciphertext", key, tampered},
+		{"truncated", key, sealed[:5]},
+		{"short key", key[:16+1], sealed},
+	}
+	for _, tt := range tests {
+		if _, err := Open(tt.key, tt.sealed); err == nil {
+			t.Errorf("%v: Open succeeded", tt.name)
+		}
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := newKey(t)
+	parsed, err := ParseKey(base64.StdEncoding.EncodeToString(key))
+	if err != nil || !bytes.Equal(parsed, key) {
+		t.Errorf("ParseKey returned %x, %v", parsed, err)
+	}
+	for _, bad := range []string{"not base64!", base64.StdEncoding.EncodeToString(key[:16])} {
+		if _, err := ParseKey(bad); err == nil {
+			t.Errorf("ParseKey(%q) succeeded", bad)
+		}
+	}
+}
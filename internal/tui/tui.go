@@ -0,0 +1,476 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/brendenwelch/gator/internal/input"
+	"github.com/brendenwelch/gator/internal/render"
+	"github.com/google/uuid"
+	"golang.org/x/term"
+)
+
+const refreshInterval = 5 * time.Second
+
+const helpLine = "tab/h/l: pane  j/k: move  enter: read  m: read/unread  s: star  o: open  r: refresh  q: quit"
+
+type pane int
+
+const (
+	feedsPane pane = iota
+	postsPane
+	bodyPane
+)
+
+type reader struct {
+	db   database.Querier
+	user database.User
+	loc  *time.Location
+	out  *bufio.Writer
+
+	feeds   []database.GetFeedsWithUnreadCountRow
+	posts   []database.GetPostsForFeedRow
+	feedIdx int
+	feedOff int
+	postIdx int
+	postOff int
+	bodyOff int
+	focus   pane
+	status  string
+}
+
+// Run takes over the terminal and shows the feeds followed by user, their
+// posts and the selected post until the user quits. Keys are read from in,
+// which must be the terminal's standard input, and dates are shown in loc.
+func Run(ctx context.Context, db database.Querier, user database.User, loc *time.Location, in *input.Reader) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("reader requires a terminal")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to put terminal in raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	r := &reader{
+		db:   db,
+		user: user,
+		loc:  loc,
+		out:  bufio.NewWriter(os.Stdout),
+	}
+	r.out.WriteString("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		r.out.WriteString("\x1b[?25h\x1b[?1049l")
+		r.out.Flush()
+	}()
+
+	if err := r.refresh(ctx); err != nil {
+		return err
+	}
+
+	// Stop reading keys when Run returns, so the next reader of in gets them.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keys := make(chan string)
+	go readKeys(ctx, in, keys)
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		r.draw()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := r.refresh(ctx); err != nil {
+				r.status = err.Error()
+			}
+		case key, ok := <-keys:
+			if !ok || key == "q" || key == "ctrl-c" {
+				return nil
+			}
+			if err := r.handleKey(ctx, key); err != nil {
+				r.status = err.Error()
+			}
+		}
+	}
+}
+
+func (r *reader) handleKey(ctx context.Context, key string) error {
+	r.status = ""
+	switch key {
+	case "tab", "l", "right":
+		if r.focus < bodyPane {
+			r.focus++
+		} else if key == "tab" {
+			r.focus = feedsPane
+		}
+	case "h", "left", "esc":
+		if r.focus > feedsPane {
+			r.focus--
+		}
+	case "j", "down":
+		return r.move(ctx, 1)
+	case "k", "up":
+		return r.move(ctx, -1)
+	case "enter":
+		post, ok := r.selectedPost()
+		if !ok {
+			return nil
+		}
+		r.focus = bodyPane
+		r.bodyOff = 0
+		if !post.Read {
+			return r.setRead(ctx, true)
+		}
+	case "m":
+		post, ok := r.selectedPost()
+		if !ok {
+			return nil
+		}
+		return r.setRead(ctx, !post.Read)
+	case "s":
+		post, ok := r.selectedPost()
+		if !ok {
+			return nil
+		}
+		if err := r.db.SetPostStarred(ctx, database.SetPostStarredParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			UserID:    r.user.ID,
+			PostID:    post.ID,
+			Starred:   !post.Starred,
+		}); err != nil {
+			return fmt.Errorf("failed to star post: %w", err)
+		}
+		return r.refresh(ctx)
+	case "o":
+		post, ok := r.selectedPost()
+		if !ok {
+			return nil
+		}
+		if err := openBrowser(post.Url); err != nil {
+			return fmt.Errorf("failed to open browser: %w", err)
+		}
+		r.status = "opened " + render.Clean(post.Url)
+	case "r":
+		return r.refresh(ctx)
+	}
+	return nil
+}
+
+func (r *reader) move(ctx context.Context, delta int) error {
+	switch r.focus {
+	case feedsPane:
+		idx := clamp(r.feedIdx+delta, len(r.feeds))
+		if idx != r.feedIdx {
+			r.feedIdx, r.postIdx, r.postOff, r.bodyOff = idx, 0, 0, 0
+			return r.loadPosts(ctx)
+		}
+	case postsPane:
+		idx := clamp(r.postIdx+delta, len(r.posts))
+		if idx != r.postIdx {
+			r.postIdx, r.bodyOff = idx, 0
+		}
+	case bodyPane:
+		r.bodyOff = max(r.bodyOff+delta, 0)
+	}
+	return nil
+}
+
+func (r *reader) setRead(ctx context.Context, read bool) error {
+	post, _ := r.selectedPost()
+	if err := r.db.SetPostRead(ctx, database.SetPostReadParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID:    r.user.ID,
+		PostID:    post.ID,
+		Read:      read,
+	}); err != nil {
+		return fmt.Errorf("failed to mark post read: %w", err)
+	}
+	return r.refresh(ctx)
+}
+
+func (r *reader) selectedPost() (database.GetPostsForFeedRow, bool) {
+	if r.postIdx >= len(r.posts) {
+		return database.GetPostsForFeedRow{}, false
+	}
+	return r.posts[r.postIdx], true
+}
+
+// refresh reloads feeds and posts, keeping the current selection where it
+// still exists so new posts from the aggregator appear in place.
+func (r *reader) refresh(ctx context.Context) error {
+	var feedID uuid.UUID
+	if r.feedIdx < len(r.feeds) {
+		feedID = r.feeds[r.feedIdx].ID
+	}
+	feeds, err := r.db.GetFeedsWithUnreadCount(ctx, r.user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feeds from db: %w", err)
+	}
+	r.feeds = feeds
+	r.feedIdx = 0
+	for i := range feeds {
+		if feeds[i].ID == feedID {
+			r.feedIdx = i
+		}
+	}
+	return r.loadPosts(ctx)
+}
+
+func (r *reader) loadPosts(ctx context.Context) error {
+	var postID uuid.UUID
+	if post, ok := r.selectedPost(); ok {
+		postID = post.ID
+	}
+	r.posts = nil
+	if r.feedIdx >= len(r.feeds) {
+		return nil
+	}
+	posts, err := r.db.GetPostsForFeed(ctx, database.GetPostsForFeedParams{
+		UserID: r.user.ID,
+		FeedID: r.feeds[r.feedIdx].ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve posts from db: %w", err)
+	}
+	r.posts = posts
+	r.postIdx = clamp(r.postIdx, len(posts))
+	for i := range posts {
+		if posts[i].ID == postID {
+			r.postIdx = i
+		}
+	}
+	return nil
+}
+
+func (r *reader) draw() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width < 40 || height < 5 {
+		width, height = 80, 24
+	}
+	rows := height - 2
+	feedsWidth := width / 5
+	postsWidth := width * 3 / 10
+	bodyWidth := width - feedsWidth - postsWidth - 2
+
+	r.feedOff = scrollTo(r.feedIdx, r.feedOff, rows)
+	r.postOff = scrollTo(r.postIdx, r.postOff, rows)
+	body := r.bodyLines(bodyWidth)
+	r.bodyOff = min(r.bodyOff, max(len(body)-rows, 0))
+
+	r.out.WriteString("\x1b[H")
+	r.out.WriteString(style(fit(fmt.Sprintf(" gator - %v", r.user.Name), width), "\x1b[1;7m"))
+	r.out.WriteString("\x1b[K\r\n")
+	for row := 0; row < rows; row++ {
+		r.out.WriteString(r.feedCell(r.feedOff+row, feedsWidth))
+		r.out.WriteString("│")
+		r.out.WriteString(r.postCell(r.postOff+row, postsWidth))
+		r.out.WriteString("│")
+		if i := r.bodyOff + row; i < len(body) {
+			r.out.WriteString(fit(body[i], bodyWidth))
+		}
+		r.out.WriteString("\x1b[K\r\n")
+	}
+	status := helpLine
+	if r.status != "" {
+		status = r.status
+	}
+	r.out.WriteString(style(fit(status, width), "\x1b[7m"))
+	r.out.WriteString("\x1b[K")
+	r.out.Flush()
+}
+
+func (r *reader) feedCell(i, width int) string {
+	if i >= len(r.feeds) {
+		return fit("", width)
+	}
+	feed := r.feeds[i]
+	label := render.Clean(feed.Name)
+	if feed.Unread > 0 {
+		label = fmt.Sprintf("%v (%v)", render.Clean(feed.Name), feed.Unread)
+	}
+	return r.cell(label, width, i == r.feedIdx, feedsPane, feed.Unread > 0)
+}
+
+func (r *reader) postCell(i, width int) string {
+	if i >= len(r.posts) {
+		return fit("", width)
+	}
+	post := r.posts[i]
+	marker := " "
+	if post.Starred {
+		marker = "*"
+	}
+	return r.cell(marker+render.Clean(post.Title), width, i == r.postIdx, postsPane, !post.Read)
+}
+
+func (r *reader) cell(label string, width int, selected bool, p pane, bold bool) string {
+	text := fit(label, width)
+	switch {
+	case selected && r.focus == p:
+		return style(text, "\x1b[7m")
+	case selected:
+		return style(text, "\x1b[4m")
+	case bold:
+		return style(text, "\x1b[1m")
+	}
+	return text
+}
+
+func (r *reader) bodyLines(width int) []string {
+	post, ok := r.selectedPost()
+	if !ok {
+		return nil
+	}
+	lines := wrap(render.Clean(post.Title), width)
+	lines = append(lines, post.PublishedAt.In(r.loc).Format(time.RFC1123), render.Clean(post.Url))
+	if post.ImageUrl != "" {
+		lines = append(lines, "image: "+render.Clean(post.ImageUrl))
+	}
+	lines = append(lines, "")
+	content := post.Description
+	if post.Content.Valid {
+		content = post.Content.String
+	}
+	body := render.Render(content, render.Options{Width: width, BaseURL: post.Url})
+	return append(lines, strings.Split(body, "\n")...)
+}
+
+func wrap(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	line := ""
+	for _, word := range words {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+// fit truncates or pads s to exactly width runes.
+func fit(s string, width int) string {
+	runes := []rune(strings.ReplaceAll(s, "\t", " "))
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+func style(s, code string) string {
+	return code + s + "\x1b[0m"
+}
+
+func scrollTo(idx, off, rows int) int {
+	if idx < off {
+		return idx
+	}
+	if idx >= off+rows {
+		return idx - rows + 1
+	}
+	return off
+}
+
+func clamp(idx, n int) int {
+	return max(min(idx, n-1), 0)
+}
+
+// openBrowser hands url to the system's browser. It only takes absolute http
+// and https URLs, since the launchers will open local files and other
+// handlers too.
+func openBrowser(url string) error {
+	u, err := neturl.Parse(url)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("refusing to open %q, it is not a web page", url)
+	}
+	url = u.String()
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		return exec.Command(browser, url).Start()
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	}
+	return exec.Command("xdg-open", url).Start()
+}
+
+// readKeys translates raw terminal input into key names and sends them on
+// keys until ctx is done or r is closed.
+func readKeys(ctx context.Context, r *input.Reader, keys chan<- string) {
+	defer close(keys)
+	send := func(key string) bool {
+		select {
+		case keys <- key:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	buf := make([]byte, 16)
+	for {
+		n, err := r.ReadContext(ctx, buf)
+		if err != nil {
+			return
+		}
+		in := buf[:n]
+		var key string
+		switch {
+		case len(in) >= 3 && in[0] == 0x1b && in[1] == '[':
+			switch in[2] {
+			case 'A':
+				key = "up"
+			case 'B':
+				key = "down"
+			case 'C':
+				key = "right"
+			case 'D':
+				key = "left"
+			}
+		case in[0] == 0x1b:
+			key = "esc"
+		case in[0] == 0x03:
+			key = "ctrl-c"
+		case in[0] == '\t':
+			key = "tab"
+		case in[0] == '\r' || in[0] == '\n':
+			key = "enter"
+		default:
+			for _, b := range in {
+				if !send(string(b)) {
+					return
+				}
+			}
+			continue
+		}
+		if key != "" && !send(key) {
+			return
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package tui
+
+import "testing"
+
+func TestOpenBrowserRefusesNonWebURLs(t *testing.T) {
+	for _, url := range []string{
+		"file:///home/alice/x.desktop",
+		"javascript:alert(1)",
+		"smb://attacker.example.com/share",
+		"-new-window",
+		"/relative/path",
+		"https:///no-host",
+	} {
+		if err := openBrowser(url); err == nil {
+			t.Errorf("openBrowser(%q) succeeded", url)
+		}
+	}
+}
@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"fmt"
 	"log"
 	"os"
+	_ "time/tzdata" // so the timezone setting works without system zone files
 
 	"github.com/brendenwelch/gator/internal/config"
 	"github.com/brendenwelch/gator/internal/database"
-	_ "github.com/lib/pq"
+	"github.com/brendenwelch/gator/internal/input"
+	"github.com/brendenwelch/gator/internal/migrate"
+	"github.com/brendenwelch/gator/internal/rss"
 )
 
 type state struct {
-	db  *database.Queries
-	cfg *config.Config
+	db       database.Store
+	cfg      *config.Config
+	fetcher  *rss.Fetcher
+	migrator *migrate.Migrator
+	stdin    *input.Reader
 }
 
 func main() {
@@ -21,45 +27,74 @@ func main() {
 		log.Fatalf("no command specified\n")
 	}
 
-	s := state{}
+	s := state{stdin: input.New(os.Stdin)}
 	cfg, err := config.Read()
 	if err != nil {
 		log.Fatalf("error reading config: %v\n", err)
 	}
 	s.cfg = &cfg
-	db, err := sql.Open("postgres", s.cfg.Db_url)
+	s.db, s.migrator, err = openDB(s.cfg)
 	if err != nil {
 		log.Fatalf("error opening database: %v\n", err)
 	}
-	s.db = database.New(db)
+	s.fetcher, err = newFetcher(s.cfg)
+	if err != nil {
+		log.Fatalf("error configuring feed fetcher: %v\n", err)
+	}
 
-	cmds := commands{
+	cmds := newCommands()
+	cmd := command{}
+	cmd.name = os.Args[1]
+	if len(os.Args) > 2 {
+		cmd.args = os.Args[2:]
+	}
+	if cmd.name != "migrate" {
+		if err := checkSchema(&s); err != nil {
+			log.Fatalf("%v\n", err)
+		}
+	}
+	if err := cmds.run(&s, cmd); err != nil {
+		log.Fatalf("%v\n", err)
+	}
+}
+
+func newCommands() *commands {
+	cmds := &commands{
 		callbacks: map[string]func(*state, command) error{},
 	}
 	cmds.register("reset", handlerReset)
 	cmds.register("register", handlerRegister)
 	cmds.register("login", handlerLogin)
 	cmds.register("users", handlerUsers)
+	cmds.register("deluser", handlerDelUser)
+	cmds.register("renameuser", handlerRenameUser)
+	cmds.register("profile", middlewareLoggedIn(handlerProfile))
 	cmds.register("addfeed", middlewareLoggedIn(handlerAddFeed))
 	cmds.register("feeds", handlerFeeds)
+	cmds.register("fullcontent", handlerFullContent)
 	cmds.register("follow", middlewareLoggedIn(handlerFollow))
 	cmds.register("unfollow", middlewareLoggedIn(handlerUnfollow))
 	cmds.register("following", middlewareLoggedIn(handlerFollowing))
 	cmds.register("agg", handlerAgg)
 	cmds.register("browse", middlewareLoggedIn(handlerBrowse))
-	cmd := command{}
-	cmd.name = os.Args[1]
-	if len(os.Args) > 2 {
-		cmd.args = os.Args[2:]
-	}
-	cmds.run(&s, cmd)
+	cmds.register("download", handlerDownload)
+	cmds.register("read", middlewareLoggedIn(handlerRead))
+	cmds.register("tui", middlewareLoggedIn(handlerTUI))
+	cmds.register("stats", handlerStats)
+	cmds.register("prune", handlerPrune)
+	cmds.register("retention", handlerRetention)
+	cmds.register("backup", handlerBackup)
+	cmds.register("restore", handlerRestore)
+	cmds.register("migrate", handlerMigrate)
+	cmds.register("shell", handlerShell(cmds))
+	return cmds
 }
 
 func middlewareLoggedIn(handler func(s *state, cmd command, user database.User) error) func(*state, command) error {
 	return func(s *state, cmd command) error {
 		user, err := s.db.GetUser(context.Background(), s.cfg.Current_user_name)
 		if err != nil {
-			log.Fatalf("failed to retrived user from db: %v\n", err)
+			return fmt.Errorf("failed to retrieve user %v from db: %w", s.cfg.Current_user_name, err)
 		}
 		return handler(s, cmd, user)
 	}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func handlerMigrate(s *state, cmd command) error {
+	if len(cmd.args) < 1 {
+		return fmt.Errorf("usage: %v up|down|status|to <version>", cmd.name)
+	}
+	s.migrator.Logf = func(format string, args ...any) {
+		fmt.Printf(format+"\n", args...)
+	}
+	ctx := context.Background()
+	switch cmd.args[0] {
+	case "up":
+		if err := s.migrator.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		if err := s.migrator.Down(ctx); err != nil {
+			return err
+		}
+	case "to":
+		if len(cmd.args) < 2 {
+			return fmt.Errorf("missing version for %v to", cmd.name)
+		}
+		version, err := strconv.ParseInt(cmd.args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %v", cmd.args[1])
+		}
+		if err := s.migrator.To(ctx, version); err != nil {
+			return err
+		}
+	case "status":
+		loc, err := s.cfg.Location()
+		if err != nil {
+			return err
+		}
+		statuses, err := s.migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				fmt.Printf("%v applied %v\n", status.Name, status.AppliedAt.In(loc).Format(time.DateTime))
+			} else {
+				fmt.Printf("%v pending\n", status.Name)
+			}
+		}
+	default:
+		return fmt.Errorf("usage: %v up|down|status|to <version>", cmd.name)
+	}
+	version, err := s.migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema version %v of %v\n", version, s.migrator.Latest())
+	return nil
+}
+
+// checkSchema refuses to go on with a database that is missing migrations
+// the generated queries depend on.
+func checkSchema(s *state) error {
+	version, err := s.migrator.Version(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to check database schema: %w", err)
+	}
+	if version < s.migrator.Latest() {
+		return fmt.Errorf("database schema is at version %v but this gator needs %v, run `gator migrate up`", version, s.migrator.Latest())
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brendenwelch/gator/internal/config"
+)
+
+func TestCheckSchemaRefusesOldSchema(t *testing.T) {
+	cfg := &config.Config{Db_url: "sqlite:" + filepath.Join(t.TempDir(), "gator.db")}
+	db, migrator, err := openDB(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &state{db: db, cfg: cfg, migrator: migrator}
+	latest := migrator.Latest()
+
+	if err := checkSchema(s); err == nil {
+		t.Error("checkSchema accepted an empty database")
+	}
+	if out := run(t, s, "migrate up"); !strings.Contains(out, "schema version "+strconv.FormatInt(latest, 10)) {
+		t.Errorf("migrate up printed %q", out)
+	}
+	if err := checkSchema(s); err != nil {
+		t.Errorf("checkSchema refused an up to date database: %v", err)
+	}
+
+	run(t, s, "migrate down")
+	if err := checkSchema(s); err == nil {
+		t.Error("checkSchema accepted a database one migration behind")
+	}
+	run(t, s, "migrate to "+strconv.FormatInt(latest, 10))
+	if version, err := migrator.Version(context.Background()); err != nil || version != latest {
+		t.Errorf("migrate to %v left the schema at %v, %v", latest, version, err)
+	}
+	if err := checkSchema(s); err != nil {
+		t.Errorf("checkSchema refused an up to date database: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/config"
+	"github.com/brendenwelch/gator/internal/database"
+)
+
+// retentionPolicy limits how many of a feed's posts are kept and for how
+// long. Zero values mean no limit.
+type retentionPolicy struct {
+	keepPosts int
+	keepFor   time.Duration
+}
+
+func defaultRetention(cfg *config.Config) (retentionPolicy, error) {
+	policy := retentionPolicy{keepPosts: cfg.Retention.Keep_posts}
+	if cfg.Retention.Keep_for != "" {
+		var err error
+		if policy.keepFor, err = time.ParseDuration(cfg.Retention.Keep_for); err != nil {
+			return policy, fmt.Errorf("failed to parse retention keep_for: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// forFeed applies the feed's own settings on top of p.
+func (p retentionPolicy) forFeed(feed database.Feed) retentionPolicy {
+	if feed.KeepPosts.Valid {
+		p.keepPosts = int(feed.KeepPosts.Int32)
+	}
+	if feed.KeepForSeconds.Valid {
+		p.keepFor = time.Duration(feed.KeepForSeconds.Int64) * time.Second
+	}
+	return p
+}
+
+func (p retentionPolicy) String() string {
+	var limits []string
+	if p.keepPosts > 0 {
+		limits = append(limits, fmt.Sprintf("at most %v posts", p.keepPosts))
+	}
+	if p.keepFor > 0 {
+		limits = append(limits, fmt.Sprintf("none older than %v", p.keepFor))
+	}
+	if len(limits) == 0 {
+		return "keep everything"
+	}
+	return "keep " + strings.Join(limits, ", ")
+}
+
+// expired reports whether the post at position i of a feed's posts, newest
+// first, falls outside the policy.
+func (p retentionPolicy) expired(i int, publishedAt, now time.Time) bool {
+	return (p.keepPosts > 0 && i >= p.keepPosts) || (p.keepFor > 0 && now.Sub(publishedAt) > p.keepFor)
+}
+
+type prunedFeed struct {
+	feed  database.Feed
+	posts []database.GetPostsForRetentionRow
+}
+
+// prunePosts removes the posts that fall outside their feed's retention
+// policy and returns them, or only returns them if dryRun is set. Starred
+// posts are never removed, and neither are unread ones if the config says
+// so.
+func prunePosts(s *state, dryRun bool) ([]prunedFeed, error) {
+	defaults, err := defaultRetention(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	feeds, err := s.db.Feeds(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve feeds from db: %w", err)
+	}
+
+	var pruned []prunedFeed
+	now := time.Now().UTC()
+	for _, feed := range feeds {
+		policy := defaults.forFeed(feed)
+		if policy.keepPosts == 0 && policy.keepFor == 0 {
+			continue
+		}
+		posts, err := s.db.GetPostsForRetention(context.Background(), feed.ID)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to retrieve posts of %v from db: %w", feed.Name, err)
+		}
+		var expired []database.GetPostsForRetentionRow
+		for i, post := range posts {
+			if post.Starred || (post.Unread && s.cfg.Retention.Keep_unread) {
+				continue
+			}
+			if policy.expired(i, post.PublishedAt, now) {
+				expired = append(expired, post)
+			}
+		}
+		if len(expired) == 0 {
+			continue
+		}
+		if !dryRun {
+			err := s.db.InTx(context.Background(), func(q database.Querier) error {
+				for _, post := range expired {
+					if err := q.DeletePost(context.Background(), post.ID); err != nil {
+						return fmt.Errorf("failed to delete post from db: %w", err)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return pruned, err
+			}
+		}
+		pruned = append(pruned, prunedFeed{feed: feed, posts: expired})
+	}
+	return pruned, nil
+}
+
+func handlerPrune(s *state, cmd command) error {
+	dryRun := false
+	for _, arg := range cmd.args {
+		if arg != "--dry-run" {
+			return fmt.Errorf("usage: %v [--dry-run]", cmd.name)
+		}
+		dryRun = true
+	}
+	loc, err := s.cfg.Location()
+	if err != nil {
+		return err
+	}
+
+	pruned, err := prunePosts(s, dryRun)
+	if err != nil {
+		return err
+	}
+	if len(pruned) == 0 {
+		fmt.Println("nothing to prune")
+		return nil
+	}
+	total := 0
+	for _, feed := range pruned {
+		total += len(feed.posts)
+		if !dryRun {
+			fmt.Printf("removed %v posts from %v\n", len(feed.posts), feed.feed.Name)
+			continue
+		}
+		fmt.Printf("would remove %v posts from %v:\n", len(feed.posts), feed.feed.Name)
+		for _, post := range feed.posts {
+			fmt.Printf("- %v (%v)\n  %v\n", post.Title, post.PublishedAt.In(loc).Format(time.DateOnly), post.Url)
+		}
+	}
+	if dryRun {
+		fmt.Printf("would remove %v posts in total\n", total)
+	} else {
+		fmt.Printf("removed %v posts in total\n", total)
+	}
+	return nil
+}
+
+func handlerRetention(s *state, cmd command) error {
+	usage := fmt.Errorf("usage: %v <url> [keep <count|default>] [for <duration|default>]", cmd.name)
+	if len(cmd.args) < 1 || len(cmd.args)%2 != 1 {
+		return usage
+	}
+	feed, err := s.db.GetFeedByURL(context.Background(), cmd.args[0])
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feed from db: %w", err)
+	}
+
+	if len(cmd.args) > 1 {
+		params := database.SetFeedRetentionParams{
+			ID:             feed.ID,
+			UpdatedAt:      time.Now().UTC(),
+			KeepPosts:      feed.KeepPosts,
+			KeepForSeconds: feed.KeepForSeconds,
+		}
+		for i := 1; i < len(cmd.args); i += 2 {
+			value := cmd.args[i+1]
+			switch cmd.args[i] {
+			case "keep":
+				params.KeepPosts = sql.NullInt32{}
+				if value != "default" {
+					n, err := strconv.ParseInt(value, 10, 32)
+					if err != nil || n < 0 {
+						return fmt.Errorf("invalid post count %v", value)
+					}
+					params.KeepPosts = sql.NullInt32{Int32: int32(n), Valid: true}
+				}
+			case "for":
+				params.KeepForSeconds = sql.NullInt64{}
+				if value != "default" {
+					d, err := time.ParseDuration(value)
+					if err != nil || d < 0 {
+						return fmt.Errorf("invalid duration %v", value)
+					}
+					params.KeepForSeconds = sql.NullInt64{Int64: int64(d / time.Second), Valid: true}
+				}
+			default:
+				return usage
+			}
+		}
+		if err := s.db.SetFeedRetention(context.Background(), params); err != nil {
+			return fmt.Errorf("failed to update feed: %w", err)
+		}
+		feed.KeepPosts, feed.KeepForSeconds = params.KeepPosts, params.KeepForSeconds
+	}
+
+	defaults, err := defaultRetention(s.cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v: %v\n", feed.Name, defaults.forFeed(feed))
+	return nil
+}
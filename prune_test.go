@@ -0,0 +1,55 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+)
+
+func TestRetentionPolicyExpired(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		policy retentionPolicy
+		i      int
+		age    time.Duration
+		want   bool
+	}{
+		{"no limits", retentionPolicy{}, 1000, 1000 * time.Hour, false},
+		{"within count", retentionPolicy{keepPosts: 3}, 2, 0, false},
+		{"beyond count", retentionPolicy{keepPosts: 3}, 3, 0, true},
+		{"within age", retentionPolicy{keepFor: 24 * time.Hour}, 50, 23 * time.Hour, false},
+		{"beyond age", retentionPolicy{keepFor: 24 * time.Hour}, 0, 25 * time.Hour, true},
+		{"both, count exceeded", retentionPolicy{keepPosts: 1, keepFor: 24 * time.Hour}, 1, time.Hour, true},
+		{"both, age exceeded", retentionPolicy{keepPosts: 10, keepFor: 24 * time.Hour}, 0, 48 * time.Hour, true},
+		{"both, within", retentionPolicy{keepPosts: 10, keepFor: 24 * time.Hour}, 9, time.Hour, false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.expired(tt.i, now.Add(-tt.age), now); got != tt.want {
+			t.Errorf("%v: expired(%v, %v old) = %v, want %v", tt.name, tt.i, tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestRetentionPolicyForFeed(t *testing.T) {
+	defaults := retentionPolicy{keepPosts: 100, keepFor: time.Hour}
+	tests := []struct {
+		name string
+		feed database.Feed
+		want string
+	}{
+		{"defaults", database.Feed{}, "keep at most 100 posts, none older than 1h0m0s"},
+		{"own count", database.Feed{KeepPosts: sql.NullInt32{Int32: 5, Valid: true}}, "keep at most 5 posts, none older than 1h0m0s"},
+		{"no limits", database.Feed{
+			KeepPosts:      sql.NullInt32{Valid: true},
+			KeepForSeconds: sql.NullInt64{Valid: true},
+		}, "keep everything"},
+	}
+	for _, tt := range tests {
+		if got := defaults.forFeed(tt.feed).String(); got != tt.want {
+			t.Errorf("%v: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+	"golang.org/x/term"
+)
+
+// handlerReset deletes everything, or with a scope flag only every post, one
+// user's follows and read state, or the fetch history of every feed. It asks
+// for confirmation unless given --yes, and can write a backup of what it is
+// about to delete first.
+func handlerReset(s *state, cmd command) error {
+	usage := fmt.Errorf("usage: %v [--posts | --user name | --fetch-state] [--backup file] [--yes]", cmd.name)
+	var scope, userName, backupPath string
+	yes := false
+	for i := 0; i < len(cmd.args); i++ {
+		switch arg := cmd.args[i]; arg {
+		case "--yes":
+			yes = true
+		case "--posts", "--fetch-state":
+			if scope != "" {
+				return usage
+			}
+			scope = arg
+		case "--user":
+			if scope != "" || i+1 >= len(cmd.args) {
+				return usage
+			}
+			scope = arg
+			i++
+			userName = cmd.args[i]
+		case "--backup":
+			if i+1 >= len(cmd.args) {
+				return usage
+			}
+			i++
+			backupPath = cmd.args[i]
+		default:
+			return usage
+		}
+	}
+
+	ctx := context.Background()
+	var user database.User
+	description := "delete every user, feed and post"
+	switch scope {
+	case "--posts":
+		description = "delete every post"
+	case "--user":
+		var err error
+		if user, err = s.db.GetUser(ctx, userName); err != nil {
+			return fmt.Errorf("failed to retrieve user %v from db: %w", userName, err)
+		}
+		description = fmt.Sprintf("delete the follows and read state of %v", user.Name)
+	case "--fetch-state":
+		description = "clear the fetch history of every feed"
+	}
+	if !yes {
+		ok, err := confirm(s, description)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("reset cancelled")
+		}
+	}
+	if backupPath != "" {
+		if err := writeBackupFile(s, backupPath, userName); err != nil {
+			return err
+		}
+		fmt.Printf("backup written to %v\n", backupPath)
+	}
+
+	var err error
+	switch scope {
+	case "":
+		// Feeds don't cascade from the users who added them.
+		err = s.db.InTx(ctx, func(q database.Querier) error {
+			if err := q.DeleteAllFeeds(ctx); err != nil {
+				return err
+			}
+			return q.Reset(ctx)
+		})
+	case "--posts":
+		err = s.db.DeleteAllPosts(ctx)
+	case "--user":
+		err = s.db.InTx(ctx, func(q database.Querier) error {
+			if err := q.DeleteFeedFollowsForUser(ctx, user.ID); err != nil {
+				return err
+			}
+			return q.DeletePostStatesForUser(ctx, user.ID)
+		})
+	case "--fetch-state":
+		err = s.db.InTx(ctx, func(q database.Querier) error {
+			if err := q.ResetFeedFetchState(ctx, time.Now().UTC()); err != nil {
+				return err
+			}
+			return q.DeleteFetches(ctx)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reset database: %w", err)
+	}
+	fmt.Println("database reset")
+	return nil
+}
+
+// confirm asks whether to go ahead and reports whether the answer was yes.
+// Piped input is meant for the command or the shell reading it, not for
+// answering prompts, so without a terminal confirm refuses and --yes is
+// needed instead.
+func confirm(s *state, description string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("refusing to %v without --yes when input is not a terminal", description)
+	}
+	fmt.Printf("This will %v. Type yes to continue: ", description)
+	// Read a byte at a time so nothing after the answer is taken from the
+	// shell.
+	var answer []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := s.stdin.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			answer = append(answer, buf[0])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read confirmation: %w", err)
+		}
+	}
+	return strings.TrimSpace(string(answer)) == "yes", nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const maxShellHistory = 1000
+
+// handlerShell keeps a single state and db connection open and runs commands
+// from the registry until exit, quit or EOF. Use login to switch users.
+func handlerShell(cmds *commands) func(*state, command) error {
+	return func(s *state, cmd command) error {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return runShell(s, cmds, &plainReader{scanner: bufio.NewScanner(s.stdin)})
+		}
+
+		history, err := loadHistory()
+		if err != nil {
+			return fmt.Errorf("failed to load shell history: %w", err)
+		}
+		t := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{s.stdin, os.Stdout}, "")
+		t.History = history
+		t.AutoCompleteCallback = completer(s, cmds)
+		if width, height, err := term.GetSize(fd); err == nil {
+			t.SetSize(width, height)
+		}
+		return runShell(s, cmds, &termReader{fd: fd, t: t})
+	}
+}
+
+func runShell(s *state, cmds *commands, r lineReader) error {
+	for {
+		line, err := r.readLine(shellPrompt(s))
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read shell input: %w", err)
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		cmd := command{name: fields[0], args: fields[1:]}
+		switch cmd.name {
+		case "exit", "quit":
+			return nil
+		case "help":
+			fmt.Println("available commands:")
+			for _, name := range shellCommands(cmds) {
+				fmt.Printf("* %v\n", name)
+			}
+			continue
+		case "shell":
+			fmt.Println("already in a shell")
+			continue
+		}
+		if err := cmds.run(s, cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+}
+
+func shellPrompt(s *state) string {
+	if s.cfg.Current_user_name == "" {
+		return "gator> "
+	}
+	return fmt.Sprintf("gator (%v)> ", s.cfg.Current_user_name)
+}
+
+func shellCommands(cmds *commands) []string {
+	names := []string{"exit", "help", "quit"}
+	for name := range cmds.callbacks {
+		if name != "shell" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// completer completes command names, and user names for login.
+func completer(s *state, cmds *commands) func(string, int, rune) (string, int, bool) {
+	return func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		head, word := "", line[:pos]
+		if i := strings.LastIndex(word, " "); i >= 0 {
+			head, word = word[:i+1], word[i+1:]
+		}
+
+		var candidates []string
+		switch strings.TrimSpace(head) {
+		case "":
+			candidates = shellCommands(cmds)
+		case "login":
+			users, err := s.db.GetUsers(context.Background())
+			if err != nil {
+				return "", 0, false
+			}
+			for _, user := range users {
+				candidates = append(candidates, user.Name)
+			}
+		default:
+			return "", 0, false
+		}
+
+		var matches []string
+		for _, c := range candidates {
+			if strings.HasPrefix(c, word) {
+				matches = append(matches, c)
+			}
+		}
+		if len(matches) == 0 {
+			return "", 0, false
+		}
+		completed := matches[0]
+		for _, m := range matches[1:] {
+			for !strings.HasPrefix(m, completed) {
+				completed = completed[:len(completed)-1]
+			}
+		}
+		if len(matches) == 1 {
+			completed += " "
+		}
+		newLine := head + completed + line[pos:]
+		return newLine, len(head) + len(completed), true
+	}
+}
+
+type lineReader interface {
+	readLine(prompt string) (string, error)
+}
+
+type plainReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plainReader) readLine(prompt string) (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+type termReader struct {
+	fd int
+	t  *term.Terminal
+}
+
+// readLine only holds the terminal in raw mode while reading, so command
+// output is printed normally.
+func (r *termReader) readLine(prompt string) (string, error) {
+	oldState, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(r.fd, oldState)
+	r.t.SetPrompt(prompt)
+	return r.t.ReadLine()
+}
+
+// fileHistory is a term.History that also appends each entry to a file, so
+// history survives between shell sessions.
+type fileHistory struct {
+	path    string
+	entries []string
+}
+
+func loadHistory() (*fileHistory, error) {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	h := &fileHistory{path: filepath.Join(dir, ".gator_history")}
+	data, err := os.ReadFile(h.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > maxShellHistory {
+		h.entries = h.entries[len(h.entries)-maxShellHistory:]
+		if err := os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0600); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+func (h *fileHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" {
+		return
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if hasCredentials(entry) {
+		// Keep secrets out of the history file.
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, entry)
+}
+
+func (h *fileHistory) Len() int {
+	return len(h.entries)
+}
+
+func (h *fileHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+func hasCredentials(line string) bool {
+	for _, field := range strings.Fields(line) {
+		switch field {
+		case "--basic", "--bearer", "--header":
+			return true
+		}
+	}
+	return false
+}
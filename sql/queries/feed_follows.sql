@@ -22,3 +22,16 @@ SELECT * FROM feed_follows WHERE user_id = $1;
 
 -- name: UnfollowFeed :exec
 DELETE FROM feed_follows WHERE user_id = $1 AND feed_id = $2;
+
+-- name: MergeFeedFollows :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+    SELECT gen_random_uuid(), feed_follows.created_at, sqlc.arg(updated_at)::TIMESTAMPTZ, feed_follows.user_id, sqlc.arg(into_feed_id)::UUID
+    FROM feed_follows
+    WHERE feed_follows.feed_id = sqlc.arg(from_feed_id)
+    ON CONFLICT (user_id, feed_id) DO NOTHING;
+
+-- name: DeleteFeedFollowsForUser :exec
+DELETE FROM feed_follows WHERE user_id = $1;
+
+-- name: GetFeedFollowsForFeed :many
+SELECT * FROM feed_follows WHERE feed_id = $1 ORDER BY created_at;
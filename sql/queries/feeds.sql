@@ -1,12 +1,13 @@
 -- name: AddFeed :one
-INSERT INTO feeds(id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds(id, created_at, updated_at, name, url, user_id, credentials)
   VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7
   )
   RETURNING *;
 
@@ -19,9 +20,11 @@ SELECT * FROM feeds WHERE url = $1;
 -- name: Feeds :many
 SELECT * FROM feeds;
 
--- name: GetNextFeedToFetch :one
+-- name: GetNextFeedsToFetch :many
 SELECT * FROM feeds
-    ORDER BY last_fetched_at ASC NULLS FIRST LIMIT 1;
+    WHERE retired_at IS NULL
+    AND (next_fetch_at IS NULL OR next_fetch_at <= sqlc.arg(now)::TIMESTAMPTZ)
+    ORDER BY last_fetched_at ASC NULLS FIRST LIMIT sqlc.arg(max_feeds);
 
 -- name: MarkFeedFetched :exec
 UPDATE feeds
@@ -29,3 +32,72 @@ UPDATE feeds
 	updated_at = $2,
 	last_fetched_at = $3
     WHERE id = $1;
+
+-- name: SetFeedFetchFullContent :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	fetch_full_content = $3
+    WHERE id = $1;
+
+-- name: SetFeedParseWarnings :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	parse_warnings = $3
+    WHERE id = $1;
+
+-- name: UpdateFeedURL :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	url = $3
+    WHERE id = $1;
+
+-- name: SetFeedNextFetch :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	next_fetch_at = $3
+    WHERE id = $1;
+
+-- name: RetireFeed :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	retired_at = $3
+    WHERE id = $1;
+
+-- name: MergeFeedPosts :exec
+UPDATE posts
+    SET feed_id = sqlc.arg(into_feed_id)
+    WHERE feed_id = sqlc.arg(from_feed_id);
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1;
+
+-- name: DeleteAllFeeds :exec
+DELETE FROM feeds;
+
+-- name: SetFeedRetention :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	keep_posts = $3,
+	keep_for_seconds = $4
+    WHERE id = $1;
+
+-- name: ResetFeedFetchState :exec
+UPDATE feeds
+    SET
+	updated_at = $1,
+	last_fetched_at = NULL,
+	next_fetch_at = NULL,
+	parse_warnings = '{}';
+
+-- name: SetFeedOwner :exec
+UPDATE feeds
+    SET
+	updated_at = $2,
+	user_id = $3
+    WHERE id = $1;
@@ -0,0 +1,31 @@
+-- name: CreateFetch :exec
+INSERT INTO fetches (id, created_at, feed_id, status_code, bytes_transferred, bytes_decoded, duration_ms, error)
+	VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		$5,
+		$6,
+		$7,
+		$8
+	);
+
+-- name: GetFetchStats :many
+SELECT
+	feeds.name,
+	feeds.url,
+	COUNT(*) AS fetches,
+	COUNT(*) FILTER (WHERE fetches.status_code <> 200)::BIGINT AS failures,
+	SUM(fetches.bytes_transferred)::BIGINT AS bytes_transferred,
+	SUM(fetches.bytes_decoded)::BIGINT AS bytes_decoded,
+	AVG(fetches.duration_ms)::BIGINT AS avg_duration_ms,
+	MAX(fetches.duration_ms)::BIGINT AS max_duration_ms,
+	(ARRAY_AGG(fetches.status_code ORDER BY fetches.created_at DESC))[1]::INTEGER AS last_status
+FROM fetches
+INNER JOIN feeds ON fetches.feed_id = feeds.id
+WHERE fetches.created_at >= $1
+GROUP BY feeds.id;
+
+-- name: DeleteFetches :exec
+DELETE FROM fetches;
@@ -0,0 +1,57 @@
+-- name: GetFeedsWithUnreadCount :many
+SELECT
+    feeds.id,
+    feeds.name,
+    feeds.url,
+    COUNT(posts.id) FILTER (WHERE COALESCE(post_states.read, FALSE) = FALSE) AS unread
+FROM feed_follows
+    INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+    LEFT JOIN posts ON posts.feed_id = feeds.id
+    LEFT JOIN post_states ON post_states.post_id = posts.id
+	AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+GROUP BY feeds.id
+ORDER BY feeds.name;
+
+-- name: GetPostsForFeed :many
+SELECT
+    posts.*,
+    COALESCE(post_states.read, FALSE)::BOOLEAN AS read,
+    COALESCE(post_states.starred, FALSE)::BOOLEAN AS starred
+FROM posts
+    LEFT JOIN post_states ON post_states.post_id = posts.id
+	AND post_states.user_id = $1
+WHERE posts.feed_id = $2
+ORDER BY posts.published_at DESC;
+
+-- name: SetPostRead :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, read)
+    VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+    )
+    ON CONFLICT (user_id, post_id) DO UPDATE
+    SET updated_at = EXCLUDED.updated_at, read = EXCLUDED.read;
+
+-- name: SetPostStarred :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, starred)
+    VALUES (
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6
+    )
+    ON CONFLICT (user_id, post_id) DO UPDATE
+    SET updated_at = EXCLUDED.updated_at, starred = EXCLUDED.starred;
+
+-- name: GetPostStatesForUser :many
+SELECT * FROM post_states WHERE user_id = $1;
+
+-- name: DeletePostStatesForUser :exec
+DELETE FROM post_states WHERE user_id = $1;
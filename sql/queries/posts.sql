@@ -1,5 +1,5 @@
--- name: CreatePost :exec
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+-- name: CreatePost :execrows
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, content, authors, categories, comments_url, image_url)
 	VALUES (
 		$1,
 		$2,
@@ -8,9 +8,14 @@ INSERT INTO posts (id, created_at, updated_at, title, url, description, publishe
 		$5,
 		$6,
 		$7,
-		$8
+		$8,
+		$9,
+		$10,
+		$11,
+		$12,
+		$13
 	)
-	RETURNING *;
+	ON CONFLICT (url) DO NOTHING;
 
 -- name: GetPostsByUser :many
 SELECT posts.* FROM posts
@@ -19,3 +24,38 @@ SELECT posts.* FROM posts
 	WHERE users.name = $1
 	ORDER BY posts.published_at ASC
 	LIMIT $2;
+
+-- name: GetPostByID :one
+SELECT * FROM posts WHERE id = $1;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts WHERE url = $1;
+
+-- name: GetPostsForRetention :many
+SELECT posts.id, posts.title, posts.url, posts.published_at,
+	EXISTS (
+		SELECT 1 FROM post_states
+		WHERE post_states.post_id = posts.id AND post_states.starred
+	) AS starred,
+	EXISTS (
+		SELECT 1 FROM feed_follows
+		WHERE feed_follows.feed_id = posts.feed_id
+		AND NOT EXISTS (
+			SELECT 1 FROM post_states
+			WHERE post_states.post_id = posts.id
+			AND post_states.user_id = feed_follows.user_id
+			AND post_states.read
+		)
+	) AS unread
+	FROM posts
+	WHERE posts.feed_id = $1
+	ORDER BY posts.published_at DESC;
+
+-- name: DeletePost :exec
+DELETE FROM posts WHERE id = $1;
+
+-- name: GetPostsByFeedID :many
+SELECT * FROM posts WHERE feed_id = $1 ORDER BY published_at;
+
+-- name: DeleteAllPosts :exec
+DELETE FROM posts;
@@ -23,3 +23,23 @@ SELECT * FROM users;
 
 -- name: Reset :exec
 DELETE FROM users;
+
+-- name: RenameUser :one
+UPDATE users
+  SET updated_at = $2, name = $3
+  WHERE id = $1
+  RETURNING *;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+  SET
+    updated_at = $2,
+    display_name = $3,
+    email = $4,
+    timezone = $5,
+    browse_limit = $6
+  WHERE id = $1
+  RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE enclosures (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	post_id UUID NOT NULL REFERENCES posts(id)
+		ON DELETE CASCADE,
+	url TEXT NOT NULL,
+	length BIGINT NOT NULL DEFAULT 0,
+	mime_type TEXT NOT NULL DEFAULT '',
+	duration TEXT NOT NULL DEFAULT '',
+	episode INTEGER,
+	season INTEGER,
+	image_url TEXT NOT NULL DEFAULT '',
+	UNIQUE(post_id, url)
+);
+
+-- +goose Down
+DROP TABLE enclosures;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN retired_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN retired_at;
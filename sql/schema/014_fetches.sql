@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE fetches (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	feed_id UUID NOT NULL REFERENCES feeds(id)
+		ON DELETE CASCADE,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	bytes_transferred BIGINT NOT NULL DEFAULT 0,
+	bytes_decoded BIGINT NOT NULL DEFAULT 0,
+	duration_ms BIGINT NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX fetches_feed_id_created_at_idx ON fetches(feed_id, created_at);
+
+-- +goose Down
+DROP TABLE fetches;
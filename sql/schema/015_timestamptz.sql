@@ -0,0 +1,67 @@
+-- +goose Up
+-- Existing values were stored without a zone, as wall clock times in the zone
+-- of the machine that wrote them. They are converted as if they were in the
+-- TimeZone of the session running this migration, so run it with PGTZ set to
+-- the writer's zone if the two differ.
+ALTER TABLE users
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE feeds
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN last_fetched_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN retired_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN next_fetch_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE feed_follows
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE posts
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN published_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE post_states
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE enclosures
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+	ALTER COLUMN updated_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE fetches
+	ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users
+	ALTER COLUMN created_at TYPE TIMESTAMP,
+	ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE feeds
+	ALTER COLUMN created_at TYPE TIMESTAMP,
+	ALTER COLUMN updated_at TYPE TIMESTAMP,
+	ALTER COLUMN last_fetched_at TYPE TIMESTAMP,
+	ALTER COLUMN retired_at TYPE TIMESTAMP,
+	ALTER COLUMN next_fetch_at TYPE TIMESTAMP;
+
+ALTER TABLE feed_follows
+	ALTER COLUMN created_at TYPE TIMESTAMP,
+	ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE posts
+	ALTER COLUMN created_at TYPE TIMESTAMP,
+	ALTER COLUMN updated_at TYPE TIMESTAMP,
+	ALTER COLUMN published_at TYPE TIMESTAMP;
+
+ALTER TABLE post_states
+	ALTER COLUMN created_at TYPE TIMESTAMP,
+	ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE enclosures
+	ALTER COLUMN created_at TYPE TIMESTAMP,
+	ALTER COLUMN updated_at TYPE TIMESTAMP;
+
+ALTER TABLE fetches
+	ALTER COLUMN created_at TYPE TIMESTAMP;
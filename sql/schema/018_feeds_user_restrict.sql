@@ -0,0 +1,14 @@
+-- +goose Up
+-- Feeds outlive the user who added them: deluser hands them to someone else
+-- first, and anything else that deletes a user still owning feeds fails
+-- instead of taking everyone's follows and posts with it.
+ALTER TABLE feeds
+	DROP CONSTRAINT feeds_user_id_fkey,
+	ADD CONSTRAINT feeds_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id)
+		ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE feeds
+	DROP CONSTRAINT feeds_user_id_fkey,
+	ADD CONSTRAINT feeds_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id)
+		ON DELETE CASCADE;
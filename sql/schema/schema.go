@@ -0,0 +1,7 @@
+// Package schema embeds the goose migrations that create gator's database.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,13 @@
+-- name: CreateEnclosure :exec
+INSERT INTO enclosures (id, created_at, updated_at, post_id, url, length, mime_type, duration, episode, season, image_url)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (post_id, url) DO NOTHING;
+
+-- name: GetEnclosuresForPost :many
+SELECT * FROM enclosures WHERE post_id = ?;
+
+-- name: GetEnclosuresForFeed :many
+SELECT enclosures.*, posts.title AS post_title, posts.published_at FROM enclosures
+	JOIN posts ON enclosures.post_id = posts.id
+	WHERE posts.feed_id = ?
+	ORDER BY posts.published_at DESC;
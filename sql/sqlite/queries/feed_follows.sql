@@ -0,0 +1,30 @@
+-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+	VALUES (?, ?, ?, ?, ?)
+	RETURNING *;
+
+-- name: GetFeedFollowNames :one
+SELECT users.name AS user_name, feeds.name AS feed_name
+FROM feed_follows
+    INNER JOIN users ON feed_follows.user_id = users.id
+    INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.id = ?;
+
+-- name: GetFeedFollowsForUser :many
+SELECT * FROM feed_follows WHERE user_id = ?;
+
+-- name: UnfollowFeed :exec
+DELETE FROM feed_follows WHERE user_id = ? AND feed_id = ?;
+
+-- name: MergeFeedFollows :exec
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+    SELECT lower(hex(randomblob(16))), feed_follows.created_at, sqlc.arg(updated_at), feed_follows.user_id, sqlc.arg(into_feed_id)
+    FROM feed_follows
+    WHERE feed_follows.feed_id = sqlc.arg(from_feed_id)
+    ON CONFLICT (user_id, feed_id) DO NOTHING;
+
+-- name: DeleteFeedFollowsForUser :exec
+DELETE FROM feed_follows WHERE user_id = ?;
+
+-- name: GetFeedFollowsForFeed :many
+SELECT * FROM feed_follows WHERE feed_id = ? ORDER BY created_at;
@@ -0,0 +1,95 @@
+-- name: AddFeed :one
+INSERT INTO feeds(id, created_at, updated_at, name, url, user_id, credentials)
+  VALUES (?, ?, ?, ?, ?, ?, ?)
+  RETURNING *;
+
+-- name: GetFeedByID :one
+SELECT * FROM feeds WHERE id = ?;
+
+-- name: GetFeedByURL :one
+SELECT * FROM feeds WHERE url = ?;
+
+-- name: Feeds :many
+SELECT * FROM feeds;
+
+-- name: GetNextFeedsToFetch :many
+SELECT * FROM feeds
+    WHERE retired_at IS NULL
+    AND (next_fetch_at IS NULL OR julianday(next_fetch_at) <= julianday(sqlc.arg(now)))
+    ORDER BY last_fetched_at ASC LIMIT sqlc.arg(max_feeds);
+
+-- name: MarkFeedFetched :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	last_fetched_at = ?
+    WHERE id = ?;
+
+-- name: SetFeedFetchFullContent :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	fetch_full_content = ?
+    WHERE id = ?;
+
+-- name: SetFeedParseWarnings :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	parse_warnings = ?
+    WHERE id = ?;
+
+-- name: UpdateFeedURL :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	url = ?
+    WHERE id = ?;
+
+-- name: SetFeedNextFetch :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	next_fetch_at = ?
+    WHERE id = ?;
+
+-- name: RetireFeed :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	retired_at = ?
+    WHERE id = ?;
+
+-- name: MergeFeedPosts :exec
+UPDATE posts
+    SET feed_id = sqlc.arg(into_feed_id)
+    WHERE feed_id = sqlc.arg(from_feed_id);
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = ?;
+
+-- name: DeleteAllFeeds :exec
+DELETE FROM feeds;
+
+-- name: SetFeedRetention :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	keep_posts = ?,
+	keep_for_seconds = ?
+    WHERE id = ?;
+
+-- name: ResetFeedFetchState :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	last_fetched_at = NULL,
+	next_fetch_at = NULL,
+	parse_warnings = '[]';
+
+-- name: SetFeedOwner :exec
+UPDATE feeds
+    SET
+	updated_at = ?,
+	user_id = ?
+    WHERE id = ?;
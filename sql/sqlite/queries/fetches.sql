@@ -0,0 +1,26 @@
+-- name: CreateFetch :exec
+INSERT INTO fetches (id, created_at, feed_id, status_code, bytes_transferred, bytes_decoded, duration_ms, error)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetFetchStats :many
+SELECT
+	feeds.name,
+	feeds.url,
+	COUNT(*) AS fetches,
+	CAST(SUM(CASE WHEN fetches.status_code <> 200 THEN 1 ELSE 0 END) AS BIGINT) AS failures,
+	CAST(SUM(fetches.bytes_transferred) AS BIGINT) AS bytes_transferred,
+	CAST(SUM(fetches.bytes_decoded) AS BIGINT) AS bytes_decoded,
+	CAST(AVG(fetches.duration_ms) AS BIGINT) AS avg_duration_ms,
+	CAST(MAX(fetches.duration_ms) AS BIGINT) AS max_duration_ms,
+	CAST((
+		SELECT latest.status_code FROM fetches AS latest
+		WHERE latest.feed_id = feeds.id
+		ORDER BY latest.created_at DESC LIMIT 1
+	) AS INTEGER) AS last_status
+FROM fetches
+INNER JOIN feeds ON fetches.feed_id = feeds.id
+WHERE fetches.created_at >= ?
+GROUP BY feeds.id;
+
+-- name: DeleteFetches :exec
+DELETE FROM fetches;
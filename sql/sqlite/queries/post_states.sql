@@ -0,0 +1,43 @@
+-- name: GetFeedsWithUnreadCount :many
+SELECT
+    feeds.id,
+    feeds.name,
+    feeds.url,
+    COUNT(posts.id) - COUNT(CASE WHEN post_states.read THEN 1 END) AS unread
+FROM feed_follows
+    INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+    LEFT JOIN posts ON posts.feed_id = feeds.id
+    LEFT JOIN post_states ON post_states.post_id = posts.id
+	AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = ?
+GROUP BY feeds.id
+ORDER BY feeds.name;
+
+-- name: GetPostsForFeed :many
+SELECT
+    posts.*,
+    CAST(COALESCE(post_states.read, FALSE) AS BOOLEAN) AS read,
+    CAST(COALESCE(post_states.starred, FALSE) AS BOOLEAN) AS starred
+FROM posts
+    LEFT JOIN post_states ON post_states.post_id = posts.id
+	AND post_states.user_id = sqlc.arg(user_id)
+WHERE posts.feed_id = sqlc.arg(feed_id)
+ORDER BY posts.published_at DESC;
+
+-- name: SetPostRead :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, read)
+    VALUES (?, ?, ?, ?, ?, ?)
+    ON CONFLICT (user_id, post_id) DO UPDATE
+    SET updated_at = excluded.updated_at, read = excluded.read;
+
+-- name: SetPostStarred :exec
+INSERT INTO post_states (id, created_at, updated_at, user_id, post_id, starred)
+    VALUES (?, ?, ?, ?, ?, ?)
+    ON CONFLICT (user_id, post_id) DO UPDATE
+    SET updated_at = excluded.updated_at, starred = excluded.starred;
+
+-- name: GetPostStatesForUser :many
+SELECT * FROM post_states WHERE user_id = ?;
+
+-- name: DeletePostStatesForUser :exec
+DELETE FROM post_states WHERE user_id = ?;
@@ -0,0 +1,47 @@
+-- name: CreatePost :execrows
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, content, authors, categories, comments_url, image_url)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (url) DO NOTHING;
+
+-- name: GetPostsByUser :many
+SELECT posts.* FROM posts
+	JOIN feeds ON posts.feed_id = feeds.id
+	JOIN users ON feeds.user_id = users.id
+	WHERE users.name = ?
+	ORDER BY posts.published_at ASC
+	LIMIT ?;
+
+-- name: GetPostByID :one
+SELECT * FROM posts WHERE id = ?;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts WHERE url = ?;
+
+-- name: GetPostsForRetention :many
+SELECT posts.id, posts.title, posts.url, posts.published_at,
+	CAST(EXISTS (
+		SELECT 1 FROM post_states
+		WHERE post_states.post_id = posts.id AND post_states.starred
+	) AS BOOLEAN) AS starred,
+	CAST(EXISTS (
+		SELECT 1 FROM feed_follows
+		WHERE feed_follows.feed_id = posts.feed_id
+		AND NOT EXISTS (
+			SELECT 1 FROM post_states
+			WHERE post_states.post_id = posts.id
+			AND post_states.user_id = feed_follows.user_id
+			AND post_states.read
+		)
+	) AS BOOLEAN) AS unread
+	FROM posts
+	WHERE posts.feed_id = ?
+	ORDER BY posts.published_at DESC;
+
+-- name: DeletePost :exec
+DELETE FROM posts WHERE id = ?;
+
+-- name: GetPostsByFeedID :many
+SELECT * FROM posts WHERE feed_id = ? ORDER BY published_at;
+
+-- name: DeleteAllPosts :exec
+DELETE FROM posts;
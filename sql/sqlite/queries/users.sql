@@ -0,0 +1,40 @@
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, name)
+  VALUES (?, ?, ?, ?)
+  RETURNING *;
+
+-- name: GetUser :one
+SELECT * FROM users
+  WHERE name = ?
+  LIMIT 1;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+  WHERE id = ?
+  LIMIT 1;
+
+-- name: GetUsers :many
+SELECT * FROM users;
+
+-- name: Reset :exec
+DELETE FROM users;
+
+-- name: RenameUser :one
+UPDATE users
+  SET updated_at = ?, name = ?
+  WHERE id = ?
+  RETURNING *;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+  SET
+    updated_at = ?,
+    display_name = ?,
+    email = ?,
+    timezone = ?,
+    browse_limit = ?
+  WHERE id = ?
+  RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = ?;
@@ -0,0 +1,103 @@
+-- +goose Up
+CREATE TABLE users (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	name TEXT UNIQUE NOT NULL
+);
+
+CREATE TABLE feeds (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	name TEXT NOT NULL,
+	url TEXT UNIQUE NOT NULL,
+	user_id UUID NOT NULL REFERENCES users(id)
+		ON DELETE CASCADE,
+	last_fetched_at TIMESTAMP,
+	fetch_full_content BOOLEAN NOT NULL DEFAULT FALSE,
+	parse_warnings TEXT NOT NULL DEFAULT '[]',
+	retired_at TIMESTAMP,
+	next_fetch_at TIMESTAMP,
+	credentials BLOB
+);
+
+CREATE TABLE feed_follows (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	user_id UUID NOT NULL REFERENCES users(id)
+		ON DELETE CASCADE,
+	feed_id UUID NOT NULL REFERENCES feeds(id)
+		ON DELETE CASCADE,
+	UNIQUE(user_id, feed_id)
+);
+
+CREATE TABLE posts (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	title TEXT NOT NULL,
+	url TEXT UNIQUE NOT NULL,
+	description TEXT NOT NULL,
+	published_at TIMESTAMP NOT NULL,
+	feed_id UUID NOT NULL REFERENCES feeds(id)
+		ON DELETE CASCADE,
+	content TEXT,
+	authors TEXT NOT NULL DEFAULT '[]',
+	categories TEXT NOT NULL DEFAULT '[]',
+	comments_url TEXT NOT NULL DEFAULT '',
+	image_url TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE post_states (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	user_id UUID NOT NULL REFERENCES users(id)
+		ON DELETE CASCADE,
+	post_id UUID NOT NULL REFERENCES posts(id)
+		ON DELETE CASCADE,
+	read BOOLEAN NOT NULL DEFAULT FALSE,
+	starred BOOLEAN NOT NULL DEFAULT FALSE,
+	UNIQUE(user_id, post_id)
+);
+
+CREATE TABLE enclosures (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	post_id UUID NOT NULL REFERENCES posts(id)
+		ON DELETE CASCADE,
+	url TEXT NOT NULL,
+	length BIGINT NOT NULL DEFAULT 0,
+	mime_type TEXT NOT NULL DEFAULT '',
+	duration TEXT NOT NULL DEFAULT '',
+	episode INTEGER,
+	season INTEGER,
+	image_url TEXT NOT NULL DEFAULT '',
+	UNIQUE(post_id, url)
+);
+
+CREATE TABLE fetches (
+	id UUID PRIMARY KEY,
+	created_at TIMESTAMP NOT NULL,
+	feed_id UUID NOT NULL REFERENCES feeds(id)
+		ON DELETE CASCADE,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	bytes_transferred BIGINT NOT NULL DEFAULT 0,
+	bytes_decoded BIGINT NOT NULL DEFAULT 0,
+	duration_ms BIGINT NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX fetches_feed_id_created_at_idx ON fetches(feed_id, created_at);
+
+-- +goose Down
+DROP TABLE fetches;
+DROP TABLE enclosures;
+DROP TABLE post_states;
+DROP TABLE posts;
+DROP TABLE feed_follows;
+DROP TABLE feeds;
+DROP TABLE users;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- SQLite keeps timestamps as text, so they only sort and compare correctly
+-- when they share a zone. Rewrite the ones stored with a local offset in UTC.
+UPDATE users SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+	updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00';
+
+UPDATE feeds SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+	updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00',
+	last_fetched_at = strftime('%Y-%m-%d %H:%M:%f', last_fetched_at) || '+00:00',
+	retired_at = strftime('%Y-%m-%d %H:%M:%f', retired_at) || '+00:00',
+	next_fetch_at = strftime('%Y-%m-%d %H:%M:%f', next_fetch_at) || '+00:00';
+
+UPDATE feed_follows SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+	updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00';
+
+UPDATE posts SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+	updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00',
+	published_at = strftime('%Y-%m-%d %H:%M:%f', published_at) || '+00:00';
+
+UPDATE post_states SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+	updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00';
+
+UPDATE enclosures SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00',
+	updated_at = strftime('%Y-%m-%d %H:%M:%f', updated_at) || '+00:00';
+
+UPDATE fetches SET
+	created_at = strftime('%Y-%m-%d %H:%M:%f', created_at) || '+00:00';
+
+-- +goose Down
+-- UTC timestamps read back as the same instants, so there is nothing to undo.
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN keep_posts INTEGER;
+ALTER TABLE feeds ADD COLUMN keep_for_seconds BIGINT;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN keep_for_seconds;
+ALTER TABLE feeds DROP COLUMN keep_posts;
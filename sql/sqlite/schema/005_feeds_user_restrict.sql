@@ -0,0 +1,14 @@
+-- +goose Up
+-- Feeds outlive the user who added them, as in the Postgres schema. SQLite
+-- can only change a foreign key by rebuilding the table, and dropping the old
+-- feeds table would cascade to every follow and post, so a trigger refuses to
+-- delete a user who still owns feeds before the cascade can run.
+CREATE TRIGGER users_restrict_feeds
+	BEFORE DELETE ON users
+	WHEN EXISTS (SELECT 1 FROM feeds WHERE feeds.user_id = OLD.id)
+BEGIN
+	SELECT RAISE(ABORT, 'FOREIGN KEY constraint failed: user still owns feeds');
+END;
+
+-- +goose Down
+DROP TRIGGER users_restrict_feeds;
@@ -0,0 +1,7 @@
+// Package schema embeds the migrations that create gator's SQLite database.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+)
+
+const (
+	defaultStatsWindow = 7 * 24 * time.Hour
+	defaultStatsTop    = 10
+)
+
+func handlerStats(s *state, cmd command) error {
+	if len(cmd.args) < 1 || cmd.args[0] != "fetch" {
+		return fmt.Errorf("usage: %v fetch [since] [count]", cmd.name)
+	}
+	return statsFetch(s, cmd.args[1:])
+}
+
+// statsFetch reports the feeds that cost the most bandwidth and took the
+// longest to fetch over the given window, e.g. "72h".
+func statsFetch(s *state, args []string) error {
+	window := defaultStatsWindow
+	top := defaultStatsTop
+	if len(args) > 0 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to parse duration %v: %w", args[0], err)
+		}
+		window = d
+	}
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid count %v", args[1])
+		}
+		top = n
+	}
+
+	rows, err := s.db.GetFetchStats(context.Background(), time.Now().UTC().Add(-window))
+	if err != nil {
+		return fmt.Errorf("failed to retrieve fetch stats from db: %w", err)
+	}
+	if len(rows) == 0 {
+		fmt.Printf("no fetches in the last %v\n", window)
+		return nil
+	}
+
+	var fetches, transferred, decoded int64
+	for _, row := range rows {
+		fetches += row.Fetches
+		transferred += row.BytesTransferred
+		decoded += row.BytesDecoded
+	}
+	fmt.Printf("%v fetches of %v feeds in the last %v: %v transferred, %v decoded\n",
+		fetches, len(rows), window, formatBytes(transferred), formatBytes(decoded))
+
+	slices.SortFunc(rows, func(a, b database.GetFetchStatsRow) int {
+		return cmp.Compare(b.BytesTransferred, a.BytesTransferred)
+	})
+	fmt.Println("\nheaviest feeds:")
+	for _, row := range rows[:min(top, len(rows))] {
+		fmt.Printf("* %v @ %v\n", row.Name, row.Url)
+		fmt.Printf("  %v transferred, %v decoded, %v per fetch\n",
+			formatBytes(row.BytesTransferred), formatBytes(row.BytesDecoded), formatBytes(row.BytesTransferred/row.Fetches))
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetFetchStatsRow) int {
+		return cmp.Compare(b.AvgDurationMs, a.AvgDurationMs)
+	})
+	fmt.Println("\nslowest feeds:")
+	for _, row := range rows[:min(top, len(rows))] {
+		fmt.Printf("* %v @ %v\n", row.Name, row.Url)
+		fmt.Printf("  %v average, %v worst, %v of %v fetches failed, last status %v\n",
+			time.Duration(row.AvgDurationMs)*time.Millisecond, time.Duration(row.MaxDurationMs)*time.Millisecond,
+			row.Failures, row.Fetches, statusText(row.LastStatus))
+	}
+	return nil
+}
+
+func statusText(code int32) string {
+	if code == 0 {
+		return "none"
+	}
+	return strconv.Itoa(int(code))
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%v B", n)
+}
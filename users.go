@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brendenwelch/gator/internal/database"
+	"github.com/google/uuid"
+)
+
+const defaultBrowseLimit = 2
+
+// handlerDelUser deletes a user along with their follows and read state. The
+// feeds they added are handed to the user named with --to, or else to
+// whoever has followed each one longest, so nobody else loses them; feeds
+// nobody else follows are deleted.
+func handlerDelUser(s *state, cmd command) error {
+	usage := fmt.Errorf("usage: %v <name> [--to name] [--yes]", cmd.name)
+	if len(cmd.args) < 1 {
+		return usage
+	}
+	var heirName string
+	yes := false
+	for i := 1; i < len(cmd.args); i++ {
+		switch cmd.args[i] {
+		case "--yes":
+			yes = true
+		case "--to":
+			if i+1 >= len(cmd.args) {
+				return usage
+			}
+			i++
+			heirName = cmd.args[i]
+		default:
+			return usage
+		}
+	}
+
+	ctx := context.Background()
+	user, err := s.db.GetUser(ctx, cmd.args[0])
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user %v from db: %w", cmd.args[0], err)
+	}
+	var heir database.User
+	if heirName != "" {
+		if heir, err = s.db.GetUser(ctx, heirName); err != nil {
+			return fmt.Errorf("failed to retrieve user %v from db: %w", heirName, err)
+		}
+		if heir.ID == user.ID {
+			return fmt.Errorf("cannot hand %v's feeds to themselves", user.Name)
+		}
+	}
+	if !yes {
+		ok, err := confirm(s, fmt.Sprintf("delete %v and their follows and read state", user.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("deluser cancelled")
+		}
+	}
+
+	users, err := s.db.GetUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve users from db: %w", err)
+	}
+	names := map[uuid.UUID]string{}
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	feeds, err := s.db.Feeds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve feeds from db: %w", err)
+	}
+
+	var changes []string
+	err = s.db.InTx(ctx, func(q database.Querier) error {
+		for _, feed := range feeds {
+			if feed.UserID != user.ID {
+				continue
+			}
+			owner := heir.ID
+			if heirName == "" {
+				follows, err := q.GetFeedFollowsForFeed(ctx, feed.ID)
+				if err != nil {
+					return fmt.Errorf("failed to retrieve feed follows from db: %w", err)
+				}
+				for _, follow := range follows {
+					if follow.UserID != user.ID {
+						owner = follow.UserID
+						break
+					}
+				}
+			}
+			if owner == uuid.Nil {
+				if err := q.DeleteFeed(ctx, feed.ID); err != nil {
+					return fmt.Errorf("failed to delete feed %v: %w", feed.Name, err)
+				}
+				changes = append(changes, fmt.Sprintf("removed %v, which nobody else follows", feed.Name))
+				continue
+			}
+			if err := q.SetFeedOwner(ctx, database.SetFeedOwnerParams{
+				ID:        feed.ID,
+				UpdatedAt: time.Now().UTC(),
+				UserID:    owner,
+			}); err != nil {
+				return fmt.Errorf("failed to reassign feed %v: %w", feed.Name, err)
+			}
+			changes = append(changes, fmt.Sprintf("%v now belongs to %v", feed.Name, names[owner]))
+		}
+		if err := q.DeleteUser(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to delete user %v: %w", user.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	fmt.Printf("%v has been deleted\n", user.Name)
+
+	if s.cfg.Current_user_name == user.Name {
+		if err := s.cfg.SetUser(""); err != nil {
+			return err
+		}
+		fmt.Println("no user is logged in now")
+	}
+	return nil
+}
+
+func handlerRenameUser(s *state, cmd command) error {
+	if len(cmd.args) != 2 {
+		return fmt.Errorf("usage: %v <name> <new name>", cmd.name)
+	}
+	oldName, newName := cmd.args[0], cmd.args[1]
+
+	ctx := context.Background()
+	user, err := s.db.GetUser(ctx, oldName)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user %v from db: %w", oldName, err)
+	}
+	if _, err := s.db.RenameUser(ctx, database.RenameUserParams{
+		ID:        user.ID,
+		UpdatedAt: time.Now().UTC(),
+		Name:      newName,
+	}); err != nil {
+		return fmt.Errorf("failed to rename user %v: %w", oldName, err)
+	}
+	fmt.Printf("%v is now %v\n", oldName, newName)
+	// Keep the config pointing at the current user under their new name.
+	if s.cfg.Current_user_name == oldName {
+		if err := s.cfg.SetUser(newName); err != nil {
+			return fmt.Errorf("failed to update the config, log in as %v again: %w", newName, err)
+		}
+	}
+	return nil
+}
+
+// handlerProfile shows the logged in user's profile, or sets or clears one
+// of its fields.
+func handlerProfile(s *state, cmd command, user database.User) error {
+	usage := fmt.Errorf("usage: %v [display_name|email|timezone|browse_limit <value|--clear>]", cmd.name)
+	if len(cmd.args) == 0 {
+		printProfile(user)
+		return nil
+	}
+	if len(cmd.args) < 2 {
+		return usage
+	}
+
+	params := database.UpdateUserProfileParams{
+		ID:          user.ID,
+		UpdatedAt:   time.Now().UTC(),
+		DisplayName: user.DisplayName,
+		Email:       user.Email,
+		Timezone:    user.Timezone,
+		BrowseLimit: user.BrowseLimit,
+	}
+	value := strings.Join(cmd.args[1:], " ")
+	clear := value == "--clear"
+	switch cmd.args[0] {
+	case "display_name":
+		params.DisplayName = ""
+		if !clear {
+			params.DisplayName = value
+		}
+	case "email":
+		params.Email = ""
+		if !clear {
+			address, err := mail.ParseAddress(value)
+			if err != nil {
+				return fmt.Errorf("invalid email %v: %w", value, err)
+			}
+			params.Email = address.Address
+		}
+	case "timezone":
+		params.Timezone = ""
+		if !clear {
+			if _, err := time.LoadLocation(value); err != nil {
+				return fmt.Errorf("invalid timezone %v: %w", value, err)
+			}
+			params.Timezone = value
+		}
+	case "browse_limit":
+		params.BrowseLimit = 0
+		if !clear {
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid browse limit %v", value)
+			}
+			params.BrowseLimit = int32(n)
+		}
+	default:
+		return usage
+	}
+
+	user, err := s.db.UpdateUserProfile(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	printProfile(user)
+	return nil
+}
+
+func printProfile(user database.User) {
+	field := func(value string) string {
+		if value == "" {
+			return "(not set)"
+		}
+		return value
+	}
+	browseLimit := fmt.Sprintf("%v (default)", defaultBrowseLimit)
+	if user.BrowseLimit > 0 {
+		browseLimit = strconv.Itoa(int(user.BrowseLimit))
+	}
+	fmt.Println(user.Name)
+	fmt.Printf("  display name: %v\n", field(user.DisplayName))
+	fmt.Printf("  email: %v\n", field(user.Email))
+	fmt.Printf("  timezone: %v\n", field(user.Timezone))
+	fmt.Printf("  browse limit: %v\n", browseLimit)
+}
+
+// userLocation is the zone dates are shown in for user: their own timezone
+// if they set one, and the configured one otherwise.
+func userLocation(s *state, user database.User) (*time.Location, error) {
+	if user.Timezone == "" {
+		return s.cfg.Location()
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q for %v: %w", user.Timezone, user.Name, err)
+	}
+	return loc, nil
+}